@@ -0,0 +1,2 @@
+ALTER TABLE "todos" ADD COLUMN "snoozed_until" date;--> statement-breakpoint
+CREATE INDEX "todos_user_id_snoozed_until_idx" ON "todos" USING btree ("user_id","snoozed_until");
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE "todos" ADD COLUMN "archived_at" timestamp;--> statement-breakpoint
+CREATE INDEX "todos_user_id_archived_at_idx" ON "todos" USING btree ("user_id","archived_at");
\ No newline at end of file
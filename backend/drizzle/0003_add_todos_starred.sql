@@ -0,0 +1,2 @@
+ALTER TABLE "todos" ADD COLUMN "starred" boolean DEFAULT false NOT NULL;--> statement-breakpoint
+CREATE INDEX "todos_user_id_starred_idx" ON "todos" USING btree ("user_id","starred");
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE "todos" ADD COLUMN "completed_at" timestamp;--> statement-breakpoint
+CREATE INDEX "todos_user_id_completed_at_idx" ON "todos" USING btree ("user_id","completed_at");--> statement-breakpoint
+UPDATE "todos" SET "completed_at" = "updated_at" WHERE "completed" = true;
\ No newline at end of file
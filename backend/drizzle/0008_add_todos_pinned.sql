@@ -0,0 +1 @@
+ALTER TABLE "todos" ADD COLUMN "pinned" boolean DEFAULT false NOT NULL;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE "todos" ADD COLUMN "reminder_offset_minutes" integer;
\ No newline at end of file
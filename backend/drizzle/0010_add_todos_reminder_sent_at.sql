@@ -0,0 +1 @@
+ALTER TABLE "todos" ADD COLUMN "reminder_sent_at" timestamp;
\ No newline at end of file
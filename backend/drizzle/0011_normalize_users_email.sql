@@ -0,0 +1,20 @@
+DO $$
+DECLARE
+  conflicts text;
+BEGIN
+  SELECT string_agg(ids, '; ')
+  INTO conflicts
+  FROM (
+    SELECT string_agg("id"::text, ', ' ORDER BY "id") AS ids
+    FROM "users"
+    GROUP BY lower(trim("email"))
+    HAVING count(*) > 1
+  ) AS duplicates;
+
+  IF conflicts IS NOT NULL THEN
+    RAISE EXCEPTION 'users.email has case-insensitive duplicates (user ids: %)', conflicts
+      USING HINT = 'Merge the duplicate accounts or change their emails so that lower(trim(email)) is unique, then run the migration again. See "Email normalization migration" in backend/README.md.';
+  END IF;
+END $$;--> statement-breakpoint
+UPDATE "users" SET "email" = lower(trim("email"));--> statement-breakpoint
+CREATE UNIQUE INDEX "users_email_lower_idx" ON "users" USING btree (lower("email"));
\ No newline at end of file
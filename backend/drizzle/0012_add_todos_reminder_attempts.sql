@@ -0,0 +1,2 @@
+ALTER TABLE "todos" ADD COLUMN "reminder_attempts" integer DEFAULT 0 NOT NULL;--> statement-breakpoint
+ALTER TABLE "todos" ADD COLUMN "reminder_retry_at" timestamp;
\ No newline at end of file
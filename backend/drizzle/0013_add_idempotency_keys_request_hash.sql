@@ -0,0 +1 @@
+ALTER TABLE "idempotency_keys" ADD COLUMN "request_hash" varchar(64);
\ No newline at end of file
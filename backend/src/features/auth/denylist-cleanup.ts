@@ -0,0 +1,32 @@
+/**
+ * JWTデナイリストの定期クリーンアップ
+ * @module features/auth/denylist-cleanup
+ */
+
+import type { JwtDenylistRepositoryInterface } from "./jwt-denylist-repository";
+
+/**
+ * 有効期限切れのデナイリストエントリを定期的に削除する
+ * @param repository - JWTデナイリストリポジトリ
+ * @param intervalMs - 実行間隔（ミリ秒）
+ * @returns クリーンアップを停止する関数
+ */
+export function startDenylistCleanup(
+  repository: JwtDenylistRepositoryInterface,
+  intervalMs: number,
+): () => void {
+  const run = async () => {
+    try {
+      const removed = await repository.cleanupExpired();
+      console.log(`JWT denylist cleanup: removed ${removed} expired entries`);
+    } catch (err) {
+      console.error("JWT denylist cleanup failed:", err);
+    }
+  };
+
+  const timer = setInterval(run, intervalMs);
+  // タイマーだけが残っている場合はプロセスの終了を妨げない
+  timer.unref();
+
+  return () => clearInterval(timer);
+}
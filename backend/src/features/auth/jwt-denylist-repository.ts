@@ -1,4 +1,4 @@
-import { eq } from "drizzle-orm";
+import { eq, lt } from "drizzle-orm";
 import type { Database } from "../../lib/db";
 import { jwtDenylists } from "../../models/schema";
 
@@ -19,6 +19,13 @@ export interface JwtDenylistRepositoryInterface {
    * @returns 存在する場合はtrue
    */
   exists(jti: string): Promise<boolean>;
+
+  /**
+   * 有効期限切れのエントリを削除する
+   * @param now - 基準日時（デフォルト: 現在日時）
+   * @returns 削除した件数
+   */
+  cleanupExpired(now?: Date): Promise<number>;
 }
 
 /**
@@ -54,4 +61,18 @@ export class JwtDenylistRepository implements JwtDenylistRepositoryInterface {
       .limit(1);
     return result.length > 0;
   }
+
+  /**
+   * 有効期限切れのエントリを削除する
+   * 期限切れのトークンはJWT検証で拒否されるため、デナイリストに残す必要はない
+   * @param now - 基準日時（デフォルト: 現在日時）
+   * @returns 削除した件数
+   */
+  async cleanupExpired(now: Date = new Date()): Promise<number> {
+    const deleted = await this.db
+      .delete(jwtDenylists)
+      .where(lt(jwtDenylists.exp, now))
+      .returning({ jti: jwtDenylists.jti });
+    return deleted.length;
+  }
 }
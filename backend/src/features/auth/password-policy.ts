@@ -0,0 +1,58 @@
+/**
+ * パスワードポリシー
+ * @module features/auth/password-policy
+ */
+
+import { getConfig } from "../../lib/config";
+
+/** パスワードポリシー */
+export interface PasswordPolicy {
+  /** 最小文字数 */
+  minLength: number;
+  /** 英小文字・英大文字・数字をすべて含める必要があるか */
+  requireMixedClasses: boolean;
+}
+
+/** 文字種ごとのチェック（requireMixedClasses有効時） */
+const CHARACTER_CLASS_RULES = [
+  { pattern: /[a-z]/, message: "パスワードには英小文字を含めてください" },
+  { pattern: /[A-Z]/, message: "パスワードには英大文字を含めてください" },
+  { pattern: /[0-9]/, message: "パスワードには数字を含めてください" },
+] as const;
+
+/**
+ * 設定からパスワードポリシーを取得する
+ * @returns パスワードポリシー
+ */
+export function getPasswordPolicy(): PasswordPolicy {
+  const config = getConfig();
+  return {
+    minLength: config.PASSWORD_MIN_LENGTH,
+    requireMixedClasses: config.PASSWORD_REQUIRE_MIXED_CLASSES,
+  };
+}
+
+/**
+ * パスワードがポリシーを満たしているか検査する
+ * 満たしていない項目ごとにエラーメッセージを返す
+ * @param password - パスワード
+ * @param policy - パスワードポリシー
+ * @returns エラーメッセージの配列（ポリシーを満たす場合は空配列）
+ */
+export function checkPasswordPolicy(password: string, policy: PasswordPolicy): string[] {
+  const errors: string[] = [];
+
+  if (password.length < policy.minLength) {
+    errors.push(`パスワードは${policy.minLength}文字以上で入力してください`);
+  }
+
+  if (policy.requireMixedClasses) {
+    for (const rule of CHARACTER_CLASS_RULES) {
+      if (!rule.pattern.test(password)) {
+        errors.push(rule.message);
+      }
+    }
+  }
+
+  return errors;
+}
@@ -8,7 +8,7 @@ import { AUTH_ERROR_MESSAGES } from "../../shared/errors/messages";
 import type { User } from "../../models/schema";
 import type { JwtDenylistRepositoryInterface } from "./jwt-denylist-repository";
 import { type TokenPayload, tokenPayloadSchema } from "./token-schema";
-import { type AuthResponse, formatUser } from "./types";
+import { type AuthResponse, formatAuthResponse, type IssuedToken } from "./types";
 import type { UserRepositoryInterface } from "./user-repository";
 
 // AuthResponseをre-export（後方互換性のため）
@@ -53,7 +53,7 @@ export class AuthService {
 
     const existingUser = await this.userRepository.findByEmail(email);
     if (existingUser) {
-      throw conflict(AUTH_ERROR_MESSAGES.EMAIL_CONFLICT);
+      throw conflict(AUTH_ERROR_MESSAGES.EMAIL_CONFLICT, "email");
     }
 
     const encryptedPassword = await bcrypt.hash(password, AUTH.BCRYPT_COST);
@@ -64,12 +64,9 @@ export class AuthService {
       name: name || null,
     });
 
-    const token = await this.generateToken(user);
+    const issued = await this.generateToken(user);
 
-    return {
-      user: formatUser(user),
-      token,
-    };
+    return formatAuthResponse(user, issued);
   }
 
   /**
@@ -90,12 +87,9 @@ export class AuthService {
       throw unauthorized(AUTH_ERROR_MESSAGES.INVALID_CREDENTIALS);
     }
 
-    const token = await this.generateToken(user);
+    const issued = await this.generateToken(user);
 
-    return {
-      user: formatUser(user),
-      token,
-    };
+    return formatAuthResponse(user, issued);
   }
 
   /**
@@ -110,24 +104,32 @@ export class AuthService {
   /**
    * JWTトークンを生成する
    * @param user - ユーザー
-   * @returns JWTトークン文字列
+   * @returns JWTトークン文字列と、iat/expクレームに設定した日時
    */
-  async generateToken(user: User): Promise<string> {
+  async generateToken(user: User): Promise<IssuedToken> {
     const config = getConfig();
     const secret = new TextEncoder().encode(config.JWT_SECRET);
     const jti = uuidv4();
 
+    // iat/expは秒単位のため、レスポンスの日時もクレームと一致させる
+    const iat = Math.floor(Date.now() / 1000);
+    const exp = iat + AUTH.JWT_EXPIRES_IN_SECONDS;
+
     const token = await new jose.SignJWT({
       sub: String(user.id),
       jti,
       email: user.email,
     })
       .setProtectedHeader({ alg: "HS256" })
-      .setIssuedAt()
-      .setExpirationTime(AUTH.JWT_EXPIRES_IN)
+      .setIssuedAt(iat)
+      .setExpirationTime(exp)
       .sign(secret);
 
-    return token;
+    return {
+      token,
+      issuedAt: new Date(iat * 1000),
+      expiresAt: new Date(exp * 1000),
+    };
   }
 
   /**
@@ -4,11 +4,21 @@
  */
 
 import type { User } from "../../models/schema";
-import type { UserResponse } from "../../shared/validators/responses";
+import type { AuthResponse, UserResponse } from "../../shared/validators/responses";
 
 // 型はresponses.tsから再エクスポート
 export type { AuthResponse, UserResponse } from "../../shared/validators/responses";
 
+/** 発行したJWTトークンと有効期間 */
+export interface IssuedToken {
+  /** JWTトークン文字列 */
+  token: string;
+  /** 発行日時（iatクレーム） */
+  issuedAt: Date;
+  /** 有効期限（expクレーム） */
+  expiresAt: Date;
+}
+
 /**
  * ユーザーをレスポンス形式にフォーマットする
  * @param user - ユーザーエンティティ
@@ -23,3 +33,18 @@ export function formatUser(user: User): UserResponse {
     updated_at: user.updatedAt.toISOString(),
   };
 }
+
+/**
+ * 認証レスポンスを作成する
+ * @param user - ユーザーエンティティ
+ * @param issued - 発行したトークン
+ * @returns 認証レスポンス
+ */
+export function formatAuthResponse(user: User, issued: IssuedToken): AuthResponse {
+  return {
+    user: formatUser(user),
+    token: issued.token,
+    issued_at: issued.issuedAt.toISOString(),
+    expires_at: issued.expiresAt.toISOString(),
+  };
+}
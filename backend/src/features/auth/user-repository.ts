@@ -1,13 +1,14 @@
-import { eq } from "drizzle-orm";
+import { eq, sql } from "drizzle-orm";
 import type { Database } from "../../lib/db";
 import { type NewUser, type User, users } from "../../models/schema";
+import { normalizeEmail } from "./validators";
 
 /**
  * ユーザーリポジトリのインターフェース
  */
 export interface UserRepositoryInterface {
   /**
-   * メールアドレスでユーザーを検索する
+   * メールアドレスでユーザーを検索する（大文字小文字・前後の空白は区別しない）
    * @param email - 検索するメールアドレス
    * @returns ユーザー、または見つからない場合はundefined
    */
@@ -40,12 +41,16 @@ export class UserRepository implements UserRepositoryInterface {
   constructor(private db: Database) {}
 
   /**
-   * メールアドレスでユーザーを検索する
+   * メールアドレスでユーザーを検索する（大文字小文字・前後の空白は区別しない）
    * @param email - 検索するメールアドレス
    * @returns ユーザー、または見つからない場合はundefined
    */
   async findByEmail(email: string): Promise<User | undefined> {
-    const result = await this.db.select().from(users).where(eq(users.email, email)).limit(1);
+    const result = await this.db
+      .select()
+      .from(users)
+      .where(eq(sql`lower(${users.email})`, normalizeEmail(email)))
+      .limit(1);
     return result.at(0);
   }
 
@@ -61,11 +66,13 @@ export class UserRepository implements UserRepositoryInterface {
 
   /**
    * 新しいユーザーを作成する
+   * メールアドレスは正規化して保存する
    * @param user - 作成するユーザー情報
    * @returns 作成されたユーザー
    */
   async create(user: NewUser): Promise<User> {
-    const result = await this.db.insert(users).values(user).returning();
+    const values = user.email === undefined ? user : { ...user, email: normalizeEmail(user.email) };
+    const result = await this.db.insert(users).values(values).returning();
     const record = result.at(0);
     if (!record) {
       throw new Error("Failed to create user");
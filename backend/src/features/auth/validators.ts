@@ -1,21 +1,36 @@
 import { z } from "zod";
 import { VALIDATION } from "../../lib/constants";
+import { checkPasswordPolicy, getPasswordPolicy } from "./password-policy";
+
+/**
+ * メールアドレスを正規化する（trim+小文字）
+ * 大文字小文字だけが異なるメールアドレスで別のアカウントを作成できないようにする
+ * @param email - メールアドレス
+ * @returns 正規化されたメールアドレス
+ */
+export function normalizeEmail(email: string): string {
+  return email.trim().toLowerCase();
+}
 
 export const signUpSchema = z
   .object({
     email: z
       .string({ error: "メールアドレスは必須です" })
+      .trim()
       .email({ error: "有効なメールアドレスを入力してください" })
       .max(VALIDATION.EMAIL_MAX_LENGTH, {
         error: `メールアドレスは${VALIDATION.EMAIL_MAX_LENGTH}文字以内で入力してください`,
-      }),
+      })
+      .transform(normalizeEmail),
     password: z
       .string({ error: "パスワードは必須です" })
-      .min(VALIDATION.PASSWORD_MIN_LENGTH, {
-        error: `パスワードは${VALIDATION.PASSWORD_MIN_LENGTH}文字以上で入力してください`,
-      })
       .max(VALIDATION.PASSWORD_MAX_LENGTH, {
         error: `パスワードは${VALIDATION.PASSWORD_MAX_LENGTH}文字以内で入力してください`,
+      })
+      .superRefine((val, ctx) => {
+        for (const message of checkPasswordPolicy(val, getPasswordPolicy())) {
+          ctx.addIssue({ code: z.ZodIssueCode.custom, message });
+        }
       }),
     password_confirmation: z.string({ error: "パスワード確認は必須です" }),
     name: z
@@ -33,7 +48,9 @@ export const signUpSchema = z
 export const signInSchema = z.object({
   email: z
     .string({ error: "メールアドレスは必須です" })
-    .email({ error: "有効なメールアドレスを入力してください" }),
+    .trim()
+    .email({ error: "有効なメールアドレスを入力してください" })
+    .transform(normalizeEmail),
   password: z.string({ error: "パスワードは必須です" }),
 });
 
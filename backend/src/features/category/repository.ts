@@ -3,9 +3,10 @@
  * @module features/category/repository
  */
 
-import { and, eq } from "drizzle-orm";
+import { and, count, eq, isNull, sql } from "drizzle-orm";
 import type { DatabaseOrTransaction } from "../../lib/db";
-import { categories } from "../../models/schema";
+import { nameMatches } from "../../lib/sql";
+import { categories, todos } from "../../models/schema";
 import type { Category, NewCategory } from "./types";
 
 /**
@@ -19,6 +20,13 @@ export interface CategoryRepositoryInterface {
    */
   findAll(userId: number): Promise<Category[]>;
 
+  /**
+   * カテゴリが未設定のTodoの件数を取得する
+   * @param userId - ユーザーID
+   * @returns 件数
+   */
+  countUncategorizedTodos(userId: number): Promise<number>;
+
   /**
    * IDとユーザーIDでカテゴリを取得する
    * @param id - カテゴリID
@@ -28,7 +36,14 @@ export interface CategoryRepositoryInterface {
   findById(id: number, userId: number): Promise<Category | undefined>;
 
   /**
-   * 名前とユーザーIDでカテゴリを取得する
+   * IDでカテゴリが存在するかを確認する（所有者を問わない）
+   * @param id - カテゴリID
+   * @returns 存在する場合true
+   */
+  existsById(id: number): Promise<boolean>;
+
+  /**
+   * 名前とユーザーIDでカテゴリを取得する（大文字小文字・アクセント記号は区別しない）
    * @param name - カテゴリ名
    * @param userId - ユーザーID
    * @returns カテゴリ、または見つからない場合はundefined
@@ -62,6 +77,21 @@ export interface CategoryRepositoryInterface {
    * @returns 削除成功した場合はtrue
    */
   delete(id: number, userId: number): Promise<boolean>;
+
+  /**
+   * カテゴリのTodoカウントを実際のTodo件数で再計算する
+   * @param id - カテゴリID
+   * @param userId - ユーザーID
+   * @returns 再計算後のカテゴリ、または見つからない場合はundefined
+   */
+  recalculateTodosCount(id: number, userId: number): Promise<Category | undefined>;
+
+  /**
+   * ユーザーのすべてのカテゴリのTodoカウントを1つのトランザクションで再計算する
+   * @param userId - ユーザーID
+   * @returns 再計算後のカテゴリの配列
+   */
+  recalculateAllTodosCounts(userId: number): Promise<Category[]>;
 }
 
 /**
@@ -78,6 +108,14 @@ export class CategoryRepository implements CategoryRepositoryInterface {
       .orderBy(categories.name);
   }
 
+  async countUncategorizedTodos(userId: number): Promise<number> {
+    const [result] = await this.db
+      .select({ count: count() })
+      .from(todos)
+      .where(and(eq(todos.userId, userId), isNull(todos.categoryId)));
+    return result?.count ?? 0;
+  }
+
   async findById(id: number, userId: number): Promise<Category | undefined> {
     const result = await this.db
       .select()
@@ -87,11 +125,20 @@ export class CategoryRepository implements CategoryRepositoryInterface {
     return result.at(0);
   }
 
+  async existsById(id: number): Promise<boolean> {
+    const result = await this.db
+      .select({ id: categories.id })
+      .from(categories)
+      .where(eq(categories.id, id))
+      .limit(1);
+    return result.length > 0;
+  }
+
   async findByName(name: string, userId: number): Promise<Category | undefined> {
     const result = await this.db
       .select()
       .from(categories)
-      .where(and(eq(categories.name, name), eq(categories.userId, userId)))
+      .where(and(nameMatches(categories.name, name), eq(categories.userId, userId)))
       .limit(1);
     return result.at(0);
   }
@@ -125,4 +172,30 @@ export class CategoryRepository implements CategoryRepositoryInterface {
       .returning({ id: categories.id });
     return result.length > 0;
   }
+
+  async recalculateTodosCount(id: number, userId: number): Promise<Category | undefined> {
+    const result = await this.db
+      .update(categories)
+      .set({
+        todosCount: sql`(SELECT count(*) FROM ${todos} WHERE ${todos.categoryId} = ${id})`,
+        updatedAt: new Date(),
+      })
+      .where(and(eq(categories.id, id), eq(categories.userId, userId)))
+      .returning();
+    return result.at(0);
+  }
+
+  async recalculateAllTodosCounts(userId: number): Promise<Category[]> {
+    return await this.db.transaction(async (tx) => {
+      const txRepository = new CategoryRepository(tx);
+      const recalculated: Category[] = [];
+      for (const category of await txRepository.findAll(userId)) {
+        const updated = await txRepository.recalculateTodosCount(category.id, userId);
+        if (updated) {
+          recalculated.push(updated);
+        }
+      }
+      return recalculated;
+    });
+  }
 }
@@ -5,11 +5,17 @@
 
 import { zValidator } from "@hono/zod-validator";
 import { Hono } from "hono";
-import { getCategoryService } from "../../lib/container";
-import { created, noContent, ok } from "../../lib/response";
+import { getCategoryService, getTodoSearchService } from "../../lib/container";
+import { created, envelope, noContent, ok } from "../../lib/response";
 import { handleValidationError } from "../../lib/validator";
 import { getCurrentUser, jwtAuth } from "../../shared/middleware/auth";
-import { createCategorySchema, idParamSchema, updateCategorySchema } from "./validators";
+import { categoryTodoSearchSchema, normalizeSearchParams } from "../todo/search-validators";
+import {
+  createCategorySchema,
+  idParamSchema,
+  listCategoryQuerySchema,
+  updateCategorySchema,
+} from "./validators";
 
 const categories = new Hono();
 
@@ -19,11 +25,30 @@ categories.use("*", jwtAuth());
 /**
  * GET /api/v1/categories
  * カテゴリ一覧を取得する
+ * envelope=true の場合は {data, meta} 形式で返す
+ * include_uncategorized=true の場合はカテゴリが未設定のTodoを表す疑似カテゴリ（id: -1）を末尾に含める
  */
-categories.get("/", async (c) => {
+categories.get(
+  "/",
+  zValidator("query", listCategoryQuerySchema, handleValidationError()),
+  async (c) => {
+    const user = getCurrentUser(c);
+    const query = c.req.valid("query");
+    const categoryService = getCategoryService();
+    const result = await categoryService.list(user.id, query.include_uncategorized);
+    return ok(c, query.envelope ? envelope(result) : result);
+  },
+);
+
+/**
+ * POST /api/v1/categories/recalculate
+ * 自分のすべてのカテゴリのtodos_countを再計算し、再計算後のカテゴリ一覧を返す
+ * /:id より前に定義する
+ */
+categories.post("/recalculate", async (c) => {
   const user = getCurrentUser(c);
   const categoryService = getCategoryService();
-  const result = await categoryService.list(user.id);
+  const result = await categoryService.recalculate(user.id);
   return ok(c, result);
 });
 
@@ -39,6 +64,30 @@ categories.get("/:id", zValidator("param", idParamSchema, handleValidationError(
   return ok(c, result);
 });
 
+/**
+ * GET /api/v1/categories/:id/todos
+ * カテゴリに属するTodoをページネーション付きで取得する
+ * ソート・フィルターは検索APIと同じパラメータを受け付ける
+ */
+categories.get(
+  "/:id/todos",
+  zValidator("param", idParamSchema, handleValidationError()),
+  zValidator("query", categoryTodoSearchSchema, handleValidationError()),
+  async (c) => {
+    const user = getCurrentUser(c);
+    const { id } = c.req.valid("param");
+    const query = c.req.valid("query");
+
+    // カテゴリの所有確認（他ユーザーのカテゴリは404）
+    await getCategoryService().show(id, user.id);
+
+    const params = { ...normalizeSearchParams(query), categoryId: id };
+    const searchService = getTodoSearchService();
+    const result = await searchService.search(params, user.id);
+    return ok(c, result);
+  },
+);
+
 /**
  * POST /api/v1/categories
  * カテゴリを作成する
@@ -6,8 +6,13 @@
 import { RESOURCE_NAMES } from "../../lib/constants";
 import { conflict, notFound, validationError } from "../../lib/errors";
 import { CATEGORY_ERROR_MESSAGES } from "../../shared/errors/messages";
+import { notFoundOrForbidden } from "../../shared/validators/ownership";
 import type { CategoryRepositoryInterface } from "./repository";
-import { type CategoryResponse, formatCategoryResponse } from "./types";
+import {
+  type CategoryResponse,
+  formatCategoryResponse,
+  formatUncategorizedResponse,
+} from "./types";
 import type { CreateCategoryInput, UpdateCategoryInput } from "./validators";
 
 /**
@@ -23,12 +28,19 @@ export class CategoryService {
 
   /**
    * ユーザーのすべてのカテゴリを取得する
+   * includeUncategorized の場合は、カテゴリが未設定のTodoを表す疑似カテゴリ（id: -1）を末尾に加える
    * @param userId - ユーザーID
+   * @param includeUncategorized - 疑似カテゴリ「uncategorized」を含めるか
    * @returns カテゴリレスポンスの配列
    */
-  async list(userId: number): Promise<CategoryResponse[]> {
+  async list(userId: number, includeUncategorized = false): Promise<CategoryResponse[]> {
     const categories = await this.categoryRepository.findAll(userId);
-    return categories.map(formatCategoryResponse);
+    const result = categories.map(formatCategoryResponse);
+    if (includeUncategorized) {
+      const count = await this.categoryRepository.countUncategorizedTodos(userId);
+      result.push(formatUncategorizedResponse(count));
+    }
+    return result;
   }
 
   /**
@@ -41,7 +53,7 @@ export class CategoryService {
   async show(id: number, userId: number): Promise<CategoryResponse> {
     const category = await this.categoryRepository.findById(id, userId);
     if (!category) {
-      throw notFound(RESOURCE_NAMES.CATEGORY, id);
+      throw await notFoundOrForbidden(RESOURCE_NAMES.CATEGORY, id, this.categoryRepository);
     }
     return formatCategoryResponse(category);
   }
@@ -57,7 +69,7 @@ export class CategoryService {
     // ユニーク制約チェック
     const existing = await this.categoryRepository.findByName(input.name, userId);
     if (existing) {
-      throw conflict(CATEGORY_ERROR_MESSAGES.DUPLICATE_NAME);
+      throw conflict(CATEGORY_ERROR_MESSAGES.DUPLICATE_NAME, "name");
     }
 
     const category = await this.categoryRepository.create({
@@ -80,14 +92,15 @@ export class CategoryService {
   async update(id: number, input: UpdateCategoryInput, userId: number): Promise<CategoryResponse> {
     const existing = await this.categoryRepository.findById(id, userId);
     if (!existing) {
-      throw notFound(RESOURCE_NAMES.CATEGORY, id);
+      throw await notFoundOrForbidden(RESOURCE_NAMES.CATEGORY, id, this.categoryRepository);
     }
 
     // 名前変更時のユニーク制約チェック
     if (input.name && input.name !== existing.name) {
       const duplicate = await this.categoryRepository.findByName(input.name, userId);
-      if (duplicate) {
-        throw conflict(CATEGORY_ERROR_MESSAGES.DUPLICATE_NAME);
+      // 大文字小文字・アクセント記号のみの変更では自分自身が一致するため除外する
+      if (duplicate && duplicate.id !== id) {
+        throw conflict(CATEGORY_ERROR_MESSAGES.DUPLICATE_NAME, "name");
       }
     }
 
@@ -101,6 +114,17 @@ export class CategoryService {
     return formatCategoryResponse(updated);
   }
 
+  /**
+   * ユーザーのすべてのカテゴリのTodoカウントを実際のTodo件数で再計算する
+   * 手動でのデータ修正などでtodos_countがずれた場合の自己修復用
+   * @param userId - ユーザーID
+   * @returns 再計算後のカテゴリレスポンスの配列
+   */
+  async recalculate(userId: number): Promise<CategoryResponse[]> {
+    const categories = await this.categoryRepository.recalculateAllTodosCounts(userId);
+    return categories.map(formatCategoryResponse);
+  }
+
   /**
    * カテゴリを削除する
    * @param id - カテゴリID
@@ -111,7 +135,7 @@ export class CategoryService {
   async destroy(id: number, userId: number): Promise<void> {
     const existing = await this.categoryRepository.findById(id, userId);
     if (!existing) {
-      throw notFound(RESOURCE_NAMES.CATEGORY, id);
+      throw await notFoundOrForbidden(RESOURCE_NAMES.CATEGORY, id, this.categoryRepository);
     }
 
     // Todo紐づきチェック
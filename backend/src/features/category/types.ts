@@ -3,6 +3,7 @@
  * @module features/category/types
  */
 
+import { CATEGORY } from "../../lib/constants";
 import type { categories } from "../../models/schema";
 
 /** カテゴリエンティティ型 */
@@ -38,3 +39,21 @@ export function formatCategoryResponse(category: Category): CategoryResponse {
     updated_at: category.updatedAt.toISOString(),
   };
 }
+
+/**
+ * カテゴリが未設定のTodoを表す疑似カテゴリのレスポンスを作成する
+ * 実在するカテゴリではないため、作成日時・更新日時はUnixエポックとする
+ * @param todosCount - カテゴリが未設定のTodoの件数
+ * @returns カテゴリレスポンス
+ */
+export function formatUncategorizedResponse(todosCount: number): CategoryResponse {
+  const epoch = new Date(0).toISOString();
+  return {
+    id: CATEGORY.UNCATEGORIZED_ID,
+    name: CATEGORY.UNCATEGORIZED_NAME,
+    color: CATEGORY.UNCATEGORIZED_COLOR,
+    todos_count: todosCount,
+    created_at: epoch,
+    updated_at: epoch,
+  };
+}
@@ -5,12 +5,16 @@
 
 import { z } from "zod";
 import { CATEGORY } from "../../lib/constants";
-import { requiredColorSchema } from "../../shared/validators/common";
+import {
+  booleanQuerySchema,
+  envelopeQuerySchema,
+  requiredColorSchema,
+} from "../../shared/validators/common";
 
 /**
  * カテゴリ作成スキーマ
  */
-export const createCategorySchema = z.object({
+export const createCategorySchema = z.strictObject({
   name: z
     .string({ message: "名前は必須です" })
     .min(1, { message: "名前は必須です" })
@@ -23,7 +27,7 @@ export const createCategorySchema = z.object({
 /**
  * カテゴリ更新スキーマ
  */
-export const updateCategorySchema = z.object({
+export const updateCategorySchema = z.strictObject({
   name: z
     .string()
     .min(1, { message: "名前は空にできません" })
@@ -34,6 +38,14 @@ export const updateCategorySchema = z.object({
   color: requiredColorSchema.optional(),
 });
 
+/**
+ * カテゴリ一覧クエリスキーマ
+ * include_uncategorized=true の場合はカテゴリが未設定のTodoを表す疑似カテゴリを含める
+ */
+export const listCategoryQuerySchema = envelopeQuerySchema.extend({
+  include_uncategorized: booleanQuerySchema.optional(),
+});
+
 // IDパラメータスキーマは共通モジュールからre-export
 export { type IdParam, idParamSchema } from "../../shared/validators/common";
 
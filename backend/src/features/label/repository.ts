@@ -0,0 +1,153 @@
+/**
+ * ラベルリポジトリ
+ * @module features/label/repository
+ */
+
+import { and, count, eq, getTableColumns, type SQL } from "drizzle-orm";
+import type { DatabaseOrTransaction } from "../../lib/db";
+import { nameMatches } from "../../lib/sql";
+import { labels, todoLabels } from "../../models/schema";
+import type { Label, LabelWithCount, NewLabel } from "./types";
+
+/**
+ * ラベルリポジトリインターフェース
+ */
+export interface LabelRepositoryInterface {
+  /**
+   * ユーザーのすべてのラベルを使用件数付きで取得する
+   * @param userId - ユーザーID
+   * @returns 使用件数付きのラベルの配列
+   */
+  findAll(userId: number): Promise<LabelWithCount[]>;
+
+  /**
+   * IDとユーザーIDでラベルを使用件数付きで取得する
+   * @param id - ラベルID
+   * @param userId - ユーザーID
+   * @returns 使用件数付きのラベル、または見つからない場合はundefined
+   */
+  findById(id: number, userId: number): Promise<LabelWithCount | undefined>;
+
+  /**
+   * IDでラベルが存在するかを確認する（所有者を問わない）
+   * @param id - ラベルID
+   * @returns 存在する場合true
+   */
+  existsById(id: number): Promise<boolean>;
+
+  /**
+   * 名前とユーザーIDでラベルを取得する（大文字小文字・アクセント記号は区別しない）
+   * @param name - ラベル名
+   * @param userId - ユーザーID
+   * @returns ラベル、または見つからない場合はundefined
+   */
+  findByName(name: string, userId: number): Promise<Label | undefined>;
+
+  /**
+   * ラベルを作成する
+   * @param data - ラベル作成データ
+   * @returns 作成されたラベル
+   */
+  create(data: NewLabel): Promise<Label>;
+
+  /**
+   * ラベルを更新する
+   * @param id - ラベルID
+   * @param userId - ユーザーID
+   * @param data - 更新データ
+   * @returns 更新されたラベル、または見つからない場合はundefined
+   */
+  update(
+    id: number,
+    userId: number,
+    data: Partial<Omit<NewLabel, "userId">>,
+  ): Promise<Label | undefined>;
+
+  /**
+   * ラベルを削除する
+   * @param id - ラベルID
+   * @param userId - ユーザーID
+   * @returns 削除成功した場合はtrue
+   */
+  delete(id: number, userId: number): Promise<boolean>;
+}
+
+/**
+ * ラベルリポジトリ実装
+ */
+export class LabelRepository implements LabelRepositoryInterface {
+  constructor(private db: DatabaseOrTransaction) {}
+
+  async findAll(userId: number): Promise<LabelWithCount[]> {
+    return await this.selectWithCount(eq(labels.userId, userId)).orderBy(labels.name);
+  }
+
+  async findById(id: number, userId: number): Promise<LabelWithCount | undefined> {
+    const result = await this.selectWithCount(
+      and(eq(labels.id, id), eq(labels.userId, userId)),
+    ).limit(1);
+    return result.at(0);
+  }
+
+  async existsById(id: number): Promise<boolean> {
+    const result = await this.db
+      .select({ id: labels.id })
+      .from(labels)
+      .where(eq(labels.id, id))
+      .limit(1);
+    return result.length > 0;
+  }
+
+  async findByName(name: string, userId: number): Promise<Label | undefined> {
+    const result = await this.db
+      .select()
+      .from(labels)
+      .where(and(nameMatches(labels.name, name), eq(labels.userId, userId)))
+      .limit(1);
+    return result.at(0);
+  }
+
+  async create(data: NewLabel): Promise<Label> {
+    const result = await this.db.insert(labels).values(data).returning();
+    const record = result.at(0);
+    if (!record) {
+      throw new Error("Failed to create label");
+    }
+    return record;
+  }
+
+  async update(
+    id: number,
+    userId: number,
+    data: Partial<Omit<NewLabel, "userId">>,
+  ): Promise<Label | undefined> {
+    const result = await this.db
+      .update(labels)
+      .set({ ...data, updatedAt: new Date() })
+      .where(and(eq(labels.id, id), eq(labels.userId, userId)))
+      .returning();
+    return result.at(0);
+  }
+
+  async delete(id: number, userId: number): Promise<boolean> {
+    const result = await this.db
+      .delete(labels)
+      .where(and(eq(labels.id, id), eq(labels.userId, userId)))
+      .returning({ id: labels.id });
+    return result.length > 0;
+  }
+
+  /**
+   * 使用件数（紐づくTodoの件数）付きでラベルを取得するクエリを作成する
+   * @param where - 絞り込み条件
+   * @returns クエリビルダー
+   */
+  private selectWithCount(where: SQL | undefined) {
+    return this.db
+      .select({ ...getTableColumns(labels), todosCount: count(todoLabels.id) })
+      .from(labels)
+      .leftJoin(todoLabels, eq(todoLabels.labelId, labels.id))
+      .where(where)
+      .groupBy(labels.id);
+  }
+}
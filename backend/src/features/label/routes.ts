@@ -0,0 +1,91 @@
+/**
+ * ラベルルートハンドラ
+ * @module features/label/routes
+ */
+
+import { zValidator } from "@hono/zod-validator";
+import { Hono } from "hono";
+import { getLabelService } from "../../lib/container";
+import { created, envelope, noContent, ok } from "../../lib/response";
+import { handleValidationError } from "../../lib/validator";
+import { getCurrentUser, jwtAuth } from "../../shared/middleware/auth";
+import {
+  createLabelSchema,
+  envelopeQuerySchema,
+  idParamSchema,
+  updateLabelSchema,
+} from "./validators";
+
+const labels = new Hono();
+
+// 全エンドポイントに認証を適用
+labels.use("*", jwtAuth());
+
+/**
+ * GET /api/v1/labels
+ * ラベル一覧を使用件数（todos_count）付きで取得する
+ * envelope=true の場合は {data, meta} 形式で返す
+ */
+labels.get("/", zValidator("query", envelopeQuerySchema, handleValidationError()), async (c) => {
+  const user = getCurrentUser(c);
+  const { envelope: useEnvelope } = c.req.valid("query");
+  const labelService = getLabelService();
+  const result = await labelService.list(user.id);
+  return ok(c, useEnvelope ? envelope(result) : result);
+});
+
+/**
+ * GET /api/v1/labels/:id
+ * ラベル詳細を取得する
+ */
+labels.get("/:id", zValidator("param", idParamSchema, handleValidationError()), async (c) => {
+  const user = getCurrentUser(c);
+  const { id } = c.req.valid("param");
+  const labelService = getLabelService();
+  const result = await labelService.show(id, user.id);
+  return ok(c, result);
+});
+
+/**
+ * POST /api/v1/labels
+ * ラベルを作成する
+ */
+labels.post("/", zValidator("json", createLabelSchema, handleValidationError()), async (c) => {
+  const user = getCurrentUser(c);
+  const body = c.req.valid("json");
+  const labelService = getLabelService();
+  const result = await labelService.create(body, user.id);
+  return created(c, result);
+});
+
+/**
+ * PATCH /api/v1/labels/:id
+ * ラベルを更新する
+ */
+labels.patch(
+  "/:id",
+  zValidator("param", idParamSchema, handleValidationError()),
+  zValidator("json", updateLabelSchema, handleValidationError()),
+  async (c) => {
+    const user = getCurrentUser(c);
+    const { id } = c.req.valid("param");
+    const body = c.req.valid("json");
+    const labelService = getLabelService();
+    const result = await labelService.update(id, body, user.id);
+    return ok(c, result);
+  },
+);
+
+/**
+ * DELETE /api/v1/labels/:id
+ * ラベルを削除する
+ */
+labels.delete("/:id", zValidator("param", idParamSchema, handleValidationError()), async (c) => {
+  const user = getCurrentUser(c);
+  const { id } = c.req.valid("param");
+  const labelService = getLabelService();
+  await labelService.destroy(id, user.id);
+  return noContent(c);
+});
+
+export default labels;
@@ -0,0 +1,123 @@
+/**
+ * ラベルサービス
+ * @module features/label/service
+ */
+
+import { RESOURCE_NAMES } from "../../lib/constants";
+import { conflict, notFound } from "../../lib/errors";
+import { LABEL_ERROR_MESSAGES } from "../../shared/errors/messages";
+import { notFoundOrForbidden } from "../../shared/validators/ownership";
+import type { LabelRepositoryInterface } from "./repository";
+import { formatLabelResponse, type LabelResponse } from "./types";
+import type { CreateLabelInput, UpdateLabelInput } from "./validators";
+
+/**
+ * ラベルサービスクラス
+ * ラベルに関するビジネスロジックを提供する
+ */
+export class LabelService {
+  /**
+   * LabelServiceを作成する
+   * @param labelRepository - ラベルリポジトリ
+   */
+  constructor(private labelRepository: LabelRepositoryInterface) {}
+
+  /**
+   * ユーザーのすべてのラベルを使用件数付きで取得する
+   * @param userId - ユーザーID
+   * @returns ラベルレスポンスの配列
+   */
+  async list(userId: number): Promise<LabelResponse[]> {
+    const labels = await this.labelRepository.findAll(userId);
+    return labels.map(formatLabelResponse);
+  }
+
+  /**
+   * ラベルの詳細を取得する
+   * @param id - ラベルID
+   * @param userId - ユーザーID
+   * @returns ラベルレスポンス
+   * @throws ラベルが見つからない場合は404エラー
+   */
+  async show(id: number, userId: number): Promise<LabelResponse> {
+    const label = await this.labelRepository.findById(id, userId);
+    if (!label) {
+      throw await notFoundOrForbidden(RESOURCE_NAMES.LABEL, id, this.labelRepository);
+    }
+    return formatLabelResponse(label);
+  }
+
+  /**
+   * ラベルを作成する
+   * @param input - ラベル作成入力
+   * @param userId - ユーザーID
+   * @returns 作成されたラベルレスポンス
+   * @throws 同じ名前のラベルが存在する場合は409エラー
+   */
+  async create(input: CreateLabelInput, userId: number): Promise<LabelResponse> {
+    // ユニーク制約チェック
+    const existing = await this.labelRepository.findByName(input.name, userId);
+    if (existing) {
+      throw conflict(LABEL_ERROR_MESSAGES.DUPLICATE_NAME, "name");
+    }
+
+    const label = await this.labelRepository.create({
+      userId,
+      name: input.name,
+      color: input.color,
+      description: input.description ?? null,
+    });
+    return formatLabelResponse({ ...label, todosCount: 0 });
+  }
+
+  /**
+   * ラベルを更新する
+   * @param id - ラベルID
+   * @param input - ラベル更新入力
+   * @param userId - ユーザーID
+   * @returns 更新されたラベルレスポンス
+   * @throws ラベルが見つからない場合は404エラー
+   * @throws 同じ名前のラベルが存在する場合は409エラー
+   */
+  async update(id: number, input: UpdateLabelInput, userId: number): Promise<LabelResponse> {
+    const existing = await this.labelRepository.findById(id, userId);
+    if (!existing) {
+      throw await notFoundOrForbidden(RESOURCE_NAMES.LABEL, id, this.labelRepository);
+    }
+
+    // 名前変更時のユニーク制約チェック
+    if (input.name && input.name !== existing.name) {
+      const duplicate = await this.labelRepository.findByName(input.name, userId);
+      // 大文字小文字・アクセント記号のみの変更では自分自身が一致するため除外する
+      if (duplicate && duplicate.id !== id) {
+        throw conflict(LABEL_ERROR_MESSAGES.DUPLICATE_NAME, "name");
+      }
+    }
+
+    const updated = await this.labelRepository.update(id, userId, {
+      name: input.name,
+      color: input.color,
+      description: input.description,
+    });
+    if (!updated) {
+      throw notFound(RESOURCE_NAMES.LABEL, id);
+    }
+    return formatLabelResponse({ ...updated, todosCount: existing.todosCount });
+  }
+
+  /**
+   * ラベルを削除する
+   * @param id - ラベルID
+   * @param userId - ユーザーID
+   * @throws ラベルが見つからない場合は404エラー
+   */
+  async destroy(id: number, userId: number): Promise<void> {
+    const existing = await this.labelRepository.findById(id, userId);
+    if (!existing) {
+      throw await notFoundOrForbidden(RESOURCE_NAMES.LABEL, id, this.labelRepository);
+    }
+
+    // todo_labelsはカスケード削除される
+    await this.labelRepository.delete(id, userId);
+  }
+}
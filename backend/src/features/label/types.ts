@@ -0,0 +1,39 @@
+/**
+ * ラベルレスポンス型とフォーマッター
+ * @module features/label/types
+ */
+
+import type { labels } from "../../models/schema";
+import type { LabelResponse } from "../../shared/validators/responses";
+
+/** ラベルエンティティ型 */
+export type Label = typeof labels.$inferSelect;
+
+/** ラベル作成用型 */
+export type NewLabel = typeof labels.$inferInsert;
+
+/** 使用件数付きのラベル */
+export type LabelWithCount = Label & {
+  /** ラベルが付いたTodoの件数 */
+  todosCount: number;
+};
+
+// レスポンス型はresponses.tsから再エクスポート
+export type { LabelResponse } from "../../shared/validators/responses";
+
+/**
+ * ラベルエンティティをレスポンス形式に変換する
+ * @param label - 使用件数付きのラベル
+ * @returns ラベルレスポンス
+ */
+export function formatLabelResponse(label: LabelWithCount): LabelResponse {
+  return {
+    id: label.id,
+    name: label.name,
+    color: label.color,
+    description: label.description,
+    todos_count: label.todosCount,
+    created_at: label.createdAt.toISOString(),
+    updated_at: label.updatedAt.toISOString(),
+  };
+}
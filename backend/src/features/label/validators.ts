@@ -0,0 +1,62 @@
+/**
+ * ラベルバリデーションスキーマ
+ * @module features/label/validators
+ */
+
+import { z } from "zod";
+import { LABEL } from "../../lib/constants";
+import { requiredColorSchema } from "../../shared/validators/common";
+
+/** 説明スキーマ */
+const descriptionSchema = z
+  .string()
+  .max(LABEL.DESCRIPTION_MAX_LENGTH, {
+    message: `説明は${LABEL.DESCRIPTION_MAX_LENGTH}文字以内で入力してください`,
+  })
+  .nullable()
+  .optional();
+
+/**
+ * ラベル作成スキーマ
+ * タグと異なり色は必須
+ */
+export const createLabelSchema = z.strictObject({
+  name: z
+    .string({ message: "名前は必須です" })
+    .trim()
+    .min(1, { message: "名前は必須です" })
+    .max(LABEL.NAME_MAX_LENGTH, {
+      message: `名前は${LABEL.NAME_MAX_LENGTH}文字以内で入力してください`,
+    }),
+  color: requiredColorSchema,
+  description: descriptionSchema,
+});
+
+/**
+ * ラベル更新スキーマ
+ */
+export const updateLabelSchema = z.strictObject({
+  name: z
+    .string()
+    .trim()
+    .min(1, { message: "名前は空にできません" })
+    .max(LABEL.NAME_MAX_LENGTH, {
+      message: `名前は${LABEL.NAME_MAX_LENGTH}文字以内で入力してください`,
+    })
+    .optional(),
+  color: requiredColorSchema.optional(),
+  description: descriptionSchema,
+});
+
+// IDパラメータスキーマは共通モジュールからre-export
+export {
+  envelopeQuerySchema,
+  type IdParam,
+  idParamSchema,
+} from "../../shared/validators/common";
+
+/** ラベル作成入力型 */
+export type CreateLabelInput = z.infer<typeof createLabelSchema>;
+
+/** ラベル更新入力型 */
+export type UpdateLabelInput = z.infer<typeof updateLabelSchema>;
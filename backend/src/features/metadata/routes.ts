@@ -0,0 +1,29 @@
+/**
+ * メタデータルートハンドラ
+ * @module features/metadata/routes
+ */
+
+import { Hono } from "hono";
+import { getCategoryService, getTagService } from "../../lib/container";
+import { ok } from "../../lib/response";
+import { getCurrentUser, jwtAuth } from "../../shared/middleware/auth";
+
+const metadata = new Hono();
+
+// 全エンドポイントに認証を適用
+metadata.use("*", jwtAuth());
+
+/**
+ * GET /api/v1/metadata
+ * アプリ起動時に必要なカテゴリ一覧とタグ一覧をまとめて取得する
+ */
+metadata.get("/", async (c) => {
+  const user = getCurrentUser(c);
+  const [categories, tags] = await Promise.all([
+    getCategoryService().list(user.id),
+    getTagService().list(user.id),
+  ]);
+  return ok(c, { categories, tags });
+});
+
+export default metadata;
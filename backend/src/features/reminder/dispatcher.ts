@@ -0,0 +1,107 @@
+/**
+ * リマインダーの定期通知
+ * @module features/reminder/dispatcher
+ */
+
+import { TODO } from "../../lib/constants";
+import { rootLogger } from "../../lib/logger";
+import type { TodoRepositoryInterface } from "../todo/todo-repository";
+import type { ReminderNotifierInterface } from "./notifier";
+
+/**
+ * 通知に失敗した回数から次回の再送日時を求める
+ * 失敗するたびに間隔を倍にし、最大回数に達した場合は再送しない
+ * @param attempts - 通知に失敗した回数（今回の失敗を含む）
+ * @param now - 基準日時
+ * @returns 次回の再送日時、または再送しない場合はnull
+ */
+export function reminderRetryAt(attempts: number, now: Date): Date | null {
+  if (attempts >= TODO.REMINDER_MAX_ATTEMPTS) {
+    return null;
+  }
+  return new Date(now.getTime() + TODO.REMINDER_RETRY_BASE_DELAY_MS * 2 ** (attempts - 1));
+}
+
+/**
+ * 通知時刻を過ぎたリマインダーを確保して通知する
+ * 通知に失敗したTodoは通知済みを取り消し、間隔を空けて再送する
+ * @param repository - Todoリポジトリ
+ * @param notifier - 通知先
+ * @param timeZone - 期限日の0時を判定するタイムゾーン
+ * @param now - 基準日時（デフォルト: 現在日時）
+ * @returns 通知した件数
+ */
+export async function dispatchReminders(
+  repository: TodoRepositoryInterface,
+  notifier: ReminderNotifierInterface,
+  timeZone: string,
+  now: Date = new Date(),
+): Promise<number> {
+  const todos = await repository.claimDueReminders(
+    now,
+    timeZone,
+    TODO.REMINDER_DISPATCH_BATCH_SIZE,
+  );
+
+  let sent = 0;
+  for (const todo of todos) {
+    try {
+      await notifier.notify(todo);
+      sent++;
+    } catch (err) {
+      const attempts = todo.reminderAttempts + 1;
+      const retryAt = reminderRetryAt(attempts, now);
+      await repository.recordReminderFailure(todo.id, retryAt);
+      rootLogger.error(
+        { err, todoId: todo.id, attempts, retryAt },
+        retryAt ? "Reminder dispatch failed" : "Reminder dispatch gave up",
+      );
+    }
+  }
+  return sent;
+}
+
+/**
+ * リマインダーの通知を定期的に実行する
+ * @param repository - Todoリポジトリ
+ * @param notifier - 通知先
+ * @param timeZone - 期限日の0時を判定するタイムゾーン
+ * @param intervalMs - 実行間隔（ミリ秒）
+ * @returns 通知を停止する関数（実行中の通知の完了を待つ）
+ */
+export function startReminderDispatch(
+  repository: TodoRepositoryInterface,
+  notifier: ReminderNotifierInterface,
+  timeZone: string,
+  intervalMs: number,
+): () => Promise<void> {
+  let running: Promise<void> | null = null;
+
+  const run = async () => {
+    try {
+      const sent = await dispatchReminders(repository, notifier, timeZone);
+      if (sent > 0) {
+        rootLogger.info({ sent }, "Reminder dispatch completed");
+      }
+    } catch (err) {
+      rootLogger.error({ err }, "Reminder dispatch failed");
+    }
+  };
+
+  const timer = setInterval(() => {
+    // 前回の実行が終わっていない場合は重複して通知しないようスキップする
+    if (running) {
+      return;
+    }
+    running = run().finally(() => {
+      running = null;
+    });
+  }, intervalMs);
+  // タイマーだけが残っている場合はプロセスの終了を妨げない
+  timer.unref();
+
+  return async () => {
+    clearInterval(timer);
+    await running;
+  };
+}
@@ -0,0 +1,80 @@
+/**
+ * リマインダーの通知先
+ * @module features/reminder/notifier
+ */
+
+import { rootLogger } from "../../lib/logger";
+import type { Todo } from "../../models/schema";
+
+/**
+ * リマインダーの通知先のインターフェース
+ */
+export interface ReminderNotifierInterface {
+  /**
+   * Todoのリマインダーを通知する
+   * @param todo - 通知するTodo
+   * @throws 通知に失敗した場合
+   */
+  notify(todo: Todo): Promise<void>;
+}
+
+/**
+ * 通知内容をログに出力する通知先（Webhook未設定時に使用）
+ */
+export class LogReminderNotifier implements ReminderNotifierInterface {
+  async notify(todo: Todo): Promise<void> {
+    rootLogger.info(
+      { todoId: todo.id, userId: todo.userId, dueDate: todo.dueDate },
+      "Reminder due",
+    );
+  }
+}
+
+/**
+ * 指定URLにJSONをPOSTする通知先
+ * 応答しないWebhookで通知ジョブが止まらないよう、タイムアウトで打ち切る
+ */
+export class WebhookReminderNotifier implements ReminderNotifierInterface {
+  /**
+   * WebhookReminderNotifierを作成する
+   * @param url - WebhookのURL
+   * @param timeoutMs - 1回の通知のタイムアウト（ミリ秒）
+   */
+  constructor(
+    private url: string,
+    private timeoutMs: number,
+  ) {}
+
+  async notify(todo: Todo): Promise<void> {
+    const response = await fetch(this.url, {
+      method: "POST",
+      signal: AbortSignal.timeout(this.timeoutMs),
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({
+        todo_id: todo.id,
+        user_id: todo.userId,
+        title: todo.title,
+        due_date: todo.dueDate,
+        reminder_offset_minutes: todo.reminderOffsetMinutes,
+      }),
+    });
+    if (!response.ok) {
+      throw new Error(`Reminder webhook responded with status ${response.status}`);
+    }
+  }
+}
+
+/**
+ * 設定に応じた通知先を作成する
+ * @param webhookUrl - WebhookのURL（省略時はログに出力する）
+ * @param timeoutMs - Webhookのタイムアウト（ミリ秒）
+ * @returns 通知先
+ */
+export function createReminderNotifier(
+  webhookUrl: string | undefined,
+  timeoutMs: number,
+): ReminderNotifierInterface {
+  return webhookUrl
+    ? new WebhookReminderNotifier(webhookUrl, timeoutMs)
+    : new LogReminderNotifier();
+}
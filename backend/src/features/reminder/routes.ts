@@ -0,0 +1,31 @@
+/**
+ * リマインダールートハンドラ
+ * @module features/reminder/routes
+ */
+
+import { zValidator } from "@hono/zod-validator";
+import { Hono } from "hono";
+import { getTodoService } from "../../lib/container";
+import { ok } from "../../lib/response";
+import { handleValidationError } from "../../lib/validator";
+import { getCurrentUser, jwtAuth } from "../../shared/middleware/auth";
+import { reminderQuerySchema } from "./validators";
+
+const reminders = new Hono();
+
+// 全エンドポイントに認証を適用
+reminders.use("*", jwtAuth());
+
+/**
+ * GET /api/v1/reminders?window=60
+ * 期限日の reminder_offset_minutes 分前の通知時刻が、現在から window 分以内に入るTodoを取得する
+ * tz で期限日の0時を判定するタイムゾーンを指定できる
+ */
+reminders.get("/", zValidator("query", reminderQuerySchema, handleValidationError()), async (c) => {
+  const user = getCurrentUser(c);
+  const query = c.req.valid("query");
+  const result = await getTodoService().reminders(user.id, query.window, query.tz);
+  return ok(c, result);
+});
+
+export default reminders;
@@ -0,0 +1,27 @@
+/**
+ * リマインダー バリデーションスキーマ
+ * @module features/reminder/validators
+ */
+
+import { z } from "zod";
+import { TODO } from "../../lib/constants";
+import { timeZoneSchema } from "../../shared/validators/common";
+
+/**
+ * リマインダー一覧クエリスキーマ
+ * window は現在時刻から何分先までを対象とするか。tz は期限日の0時を判定するタイムゾーン
+ */
+export const reminderQuerySchema = z.object({
+  window: z.coerce
+    .number({ message: "window は数値で指定してください" })
+    .int({ message: "window は整数で指定してください" })
+    .min(1, { message: "window は1以上で指定してください" })
+    .max(TODO.REMINDER_WINDOW_MAX_MINUTES, {
+      message: `window は${TODO.REMINDER_WINDOW_MAX_MINUTES}以下で指定してください`,
+    })
+    .default(TODO.REMINDER_WINDOW_DEFAULT_MINUTES),
+  tz: timeZoneSchema.optional(),
+});
+
+/** リマインダー一覧クエリ入力型 */
+export type ReminderQuery = z.infer<typeof reminderQuerySchema>;
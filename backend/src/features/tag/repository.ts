@@ -5,6 +5,7 @@
 
 import { and, eq } from "drizzle-orm";
 import type { DatabaseOrTransaction } from "../../lib/db";
+import { nameMatches } from "../../lib/sql";
 import { tags } from "../../models/schema";
 import type { NewTag, Tag } from "./types";
 
@@ -28,7 +29,14 @@ export interface TagRepositoryInterface {
   findById(id: number, userId: number): Promise<Tag | undefined>;
 
   /**
-   * 名前とユーザーIDでタグを取得する
+   * IDでタグが存在するかを確認する（所有者を問わない）
+   * @param id - タグID
+   * @returns 存在する場合true
+   */
+  existsById(id: number): Promise<boolean>;
+
+  /**
+   * 名前とユーザーIDでタグを取得する（大文字小文字・アクセント記号は区別しない）
    * @param name - タグ名（正規化済み）
    * @param userId - ユーザーID
    * @returns タグ、または見つからない場合はundefined
@@ -83,11 +91,20 @@ export class TagRepository implements TagRepositoryInterface {
     return result.at(0);
   }
 
+  async existsById(id: number): Promise<boolean> {
+    const result = await this.db
+      .select({ id: tags.id })
+      .from(tags)
+      .where(eq(tags.id, id))
+      .limit(1);
+    return result.length > 0;
+  }
+
   async findByName(name: string, userId: number): Promise<Tag | undefined> {
     const result = await this.db
       .select()
       .from(tags)
-      .where(and(eq(tags.name, name), eq(tags.userId, userId)))
+      .where(and(nameMatches(tags.name, name), eq(tags.userId, userId)))
       .limit(1);
     return result.at(0);
   }
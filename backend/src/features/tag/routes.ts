@@ -5,11 +5,17 @@
 
 import { zValidator } from "@hono/zod-validator";
 import { Hono } from "hono";
-import { getTagService } from "../../lib/container";
-import { created, noContent, ok } from "../../lib/response";
+import { getTagService, getTodoSearchService } from "../../lib/container";
+import { created, envelope, noContent, ok } from "../../lib/response";
 import { handleValidationError } from "../../lib/validator";
 import { getCurrentUser, jwtAuth } from "../../shared/middleware/auth";
-import { createTagSchema, idParamSchema, updateTagSchema } from "./validators";
+import { normalizeSearchParams, tagTodoSearchSchema } from "../todo/search-validators";
+import {
+  createTagSchema,
+  envelopeQuerySchema,
+  idParamSchema,
+  updateTagSchema,
+} from "./validators";
 
 const tags = new Hono();
 
@@ -19,12 +25,14 @@ tags.use("*", jwtAuth());
 /**
  * GET /api/v1/tags
  * タグ一覧を取得する
+ * envelope=true の場合は {data, meta} 形式で返す
  */
-tags.get("/", async (c) => {
+tags.get("/", zValidator("query", envelopeQuerySchema, handleValidationError()), async (c) => {
   const user = getCurrentUser(c);
+  const { envelope: useEnvelope } = c.req.valid("query");
   const tagService = getTagService();
   const result = await tagService.list(user.id);
-  return ok(c, result);
+  return ok(c, useEnvelope ? envelope(result) : result);
 });
 
 /**
@@ -39,6 +47,30 @@ tags.get("/:id", zValidator("param", idParamSchema, handleValidationError()), as
   return ok(c, result);
 });
 
+/**
+ * GET /api/v1/tags/:id/todos
+ * タグが付いたTodoをページネーション付きで取得する
+ * ソート・フィルターは検索APIと同じパラメータを受け付ける
+ */
+tags.get(
+  "/:id/todos",
+  zValidator("param", idParamSchema, handleValidationError()),
+  zValidator("query", tagTodoSearchSchema, handleValidationError()),
+  async (c) => {
+    const user = getCurrentUser(c);
+    const { id } = c.req.valid("param");
+    const query = c.req.valid("query");
+
+    // タグの所有確認（他ユーザーのタグは404）
+    await getTagService().show(id, user.id);
+
+    const params = { ...normalizeSearchParams(query), tagIds: [id] };
+    const searchService = getTodoSearchService();
+    const result = await searchService.search(params, user.id);
+    return ok(c, result);
+  },
+);
+
 /**
  * POST /api/v1/tags
  * タグを作成する
@@ -3,13 +3,29 @@
  * @module features/tag/service
  */
 
-import { RESOURCE_NAMES } from "../../lib/constants";
+import { RESOURCE_NAMES, TAG } from "../../lib/constants";
 import { conflict, notFound } from "../../lib/errors";
 import { TAG_ERROR_MESSAGES } from "../../shared/errors/messages";
+import { notFoundOrForbidden } from "../../shared/validators/ownership";
 import type { TagRepositoryInterface } from "./repository";
 import { formatTagResponse, type TagResponse } from "./types";
 import type { CreateTagInput, UpdateTagInput } from "./validators";
 
+/**
+ * タグ名からデフォルトの色を決定する
+ * 名前のハッシュ値（FNV-1a）で候補の色を選ぶため、同じ名前には常に同じ色を返す
+ * @param name - タグ名（正規化済み）
+ * @returns #RRGGBB形式の色
+ */
+export function defaultTagColor(name: string): string {
+  let hash = 0x811c9dc5;
+  for (const char of name) {
+    hash ^= char.codePointAt(0) ?? 0;
+    hash = Math.imul(hash, 0x01000193) >>> 0;
+  }
+  return TAG.DEFAULT_COLORS[hash % TAG.DEFAULT_COLORS.length] ?? TAG.DEFAULT_COLORS[0];
+}
+
 /**
  * タグサービスクラス
  * タグに関するビジネスロジックを提供する
@@ -18,8 +34,12 @@ export class TagService {
   /**
    * TagServiceを作成する
    * @param tagRepository - タグリポジトリ
+   * @param defaultColorEnabled - 色を省略して作成したタグに名前からデフォルトの色を割り当てるか
    */
-  constructor(private tagRepository: TagRepositoryInterface) {}
+  constructor(
+    private tagRepository: TagRepositoryInterface,
+    private defaultColorEnabled = false,
+  ) {}
 
   /**
    * ユーザーのすべてのタグを取得する
@@ -41,13 +61,14 @@ export class TagService {
   async show(id: number, userId: number): Promise<TagResponse> {
     const tag = await this.tagRepository.findById(id, userId);
     if (!tag) {
-      throw notFound(RESOURCE_NAMES.TAG, id);
+      throw await notFoundOrForbidden(RESOURCE_NAMES.TAG, id, this.tagRepository);
     }
     return formatTagResponse(tag);
   }
 
   /**
    * タグを作成する
+   * 色を省略した場合、デフォルト色が有効なら名前から色を割り当てる（null を明示した場合は色なし）
    * @param input - タグ作成入力（名前は正規化済み）
    * @param userId - ユーザーID
    * @returns 作成されたタグレスポンス
@@ -57,13 +78,13 @@ export class TagService {
     // ユニーク制約チェック（正規化後の名前で）
     const existing = await this.tagRepository.findByName(input.name, userId);
     if (existing) {
-      throw conflict(TAG_ERROR_MESSAGES.DUPLICATE_NAME);
+      throw conflict(TAG_ERROR_MESSAGES.DUPLICATE_NAME, "name");
     }
 
     const tag = await this.tagRepository.create({
       userId,
       name: input.name,
-      color: input.color ?? null,
+      color: this.resolveColor(input),
     });
     return formatTagResponse(tag);
   }
@@ -80,14 +101,15 @@ export class TagService {
   async update(id: number, input: UpdateTagInput, userId: number): Promise<TagResponse> {
     const existing = await this.tagRepository.findById(id, userId);
     if (!existing) {
-      throw notFound(RESOURCE_NAMES.TAG, id);
+      throw await notFoundOrForbidden(RESOURCE_NAMES.TAG, id, this.tagRepository);
     }
 
     // 名前変更時のユニーク制約チェック
     if (input.name && input.name !== existing.name) {
       const duplicate = await this.tagRepository.findByName(input.name, userId);
-      if (duplicate) {
-        throw conflict(TAG_ERROR_MESSAGES.DUPLICATE_NAME);
+      // 大文字小文字・アクセント記号のみの変更では自分自身が一致するため除外する
+      if (duplicate && duplicate.id !== id) {
+        throw conflict(TAG_ERROR_MESSAGES.DUPLICATE_NAME, "name");
       }
     }
 
@@ -101,6 +123,18 @@ export class TagService {
     return formatTagResponse(updated);
   }
 
+  /**
+   * 作成するタグの色を決定する
+   * @param input - タグ作成入力
+   * @returns 色、または色なしの場合はnull
+   */
+  private resolveColor(input: CreateTagInput): string | null {
+    if (input.color !== undefined) {
+      return input.color;
+    }
+    return this.defaultColorEnabled ? defaultTagColor(input.name) : null;
+  }
+
   /**
    * タグを削除する
    * @param id - タグID
@@ -110,7 +144,7 @@ export class TagService {
   async destroy(id: number, userId: number): Promise<void> {
     const existing = await this.tagRepository.findById(id, userId);
     if (!existing) {
-      throw notFound(RESOURCE_NAMES.TAG, id);
+      throw await notFoundOrForbidden(RESOURCE_NAMES.TAG, id, this.tagRepository);
     }
 
     // todo_tagsはカスケード削除される
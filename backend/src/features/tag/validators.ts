@@ -19,7 +19,7 @@ function normalizeTagName(name: string): string {
 /**
  * タグ作成スキーマ
  */
-export const createTagSchema = z.object({
+export const createTagSchema = z.strictObject({
   name: z
     .string({ message: "名前は必須です" })
     .min(1, { message: "名前は必須です" })
@@ -33,7 +33,7 @@ export const createTagSchema = z.object({
 /**
  * タグ更新スキーマ
  */
-export const updateTagSchema = z.object({
+export const updateTagSchema = z.strictObject({
   name: z
     .string()
     .min(1, { message: "名前は空にできません" })
@@ -46,7 +46,11 @@ export const updateTagSchema = z.object({
 });
 
 // IDパラメータスキーマは共通モジュールからre-export
-export { type IdParam, idParamSchema } from "../../shared/validators/common";
+export {
+  envelopeQuerySchema,
+  type IdParam,
+  idParamSchema,
+} from "../../shared/validators/common";
 
 /** タグ作成入力型 */
 export type CreateTagInput = z.infer<typeof createTagSchema>;
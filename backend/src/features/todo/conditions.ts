@@ -0,0 +1,48 @@
+/**
+ * Todo共通クエリ条件
+ * 一覧・検索で共有する表示対象の絞り込み条件を提供する
+ * @module features/todo/conditions
+ */
+
+import { isNotNull, isNull, type SQL, sql } from "drizzle-orm";
+import { todos } from "../../models/schema";
+
+/**
+ * スヌーズ中でないTodoに絞り込む条件
+ * snoozed_until が未設定、または今日以前のTodoを対象とする
+ * スヌーズ期限の検証と同じ日付で判定するよう、DBの CURRENT_DATE ではなくアプリ側の今日を渡す
+ * @param today - 今日の日付（YYYY-MM-DD）
+ * @returns SQL条件
+ */
+export function notSnoozedCondition(today: string): SQL {
+  return sql`(${todos.snoozedUntil} IS NULL OR ${todos.snoozedUntil} <= ${today})`;
+}
+
+/**
+ * アーカイブ状態でTodoを絞り込む条件
+ * @param archived - trueでアーカイブ済み、falseで未アーカイブのTodoを対象とする
+ * @returns SQL条件
+ */
+export function archivedCondition(archived: boolean): SQL {
+  return archived ? isNotNull(todos.archivedAt) : isNull(todos.archivedAt);
+}
+
+/**
+ * 期限日の0時（指定タイムゾーン）の日時を表す式
+ * @param timeZone - 期限日の0時を判定するタイムゾーン
+ * @returns SQL式（期限日が未設定の場合はNULL）
+ */
+export function dueAtExpression(timeZone: string): SQL {
+  // 期限日は日付のみのため、指定タイムゾーンの0時として解釈する
+  return sql`(${todos.dueDate}::timestamp AT TIME ZONE ${timeZone})`;
+}
+
+/**
+ * リマインダーの通知時刻（期限日の0時から reminder_offset_minutes 分前）を表す式
+ * @param timeZone - 期限日の0時を判定するタイムゾーン
+ * @returns SQL式（期限日かオフセットが未設定の場合はNULL）
+ */
+export function remindAtExpression(timeZone: string): SQL {
+  const offset = sql`make_interval(mins => ${todos.reminderOffsetMinutes})`;
+  return sql`(${dueAtExpression(timeZone)} - ${offset})`;
+}
@@ -0,0 +1,67 @@
+/**
+ * Todo検索のハイライト抽出
+ * @module features/todo/highlight
+ */
+
+/** 一致箇所の前後に含める文字数 */
+const SNIPPET_CONTEXT_LENGTH = 40;
+
+/** 抜粋が省略されていることを示す記号 */
+const ELLIPSIS = "…";
+
+/**
+ * 検索キーワードに一致した箇所のハイライト
+ */
+export interface TodoHighlight {
+  /** 一致したフィールド */
+  field: "title" | "description";
+  /** 一致箇所周辺の抜粋 */
+  snippet: string;
+}
+
+/**
+ * テキストから一致箇所の前後を切り出す
+ * @param text - 対象テキスト
+ * @param index - 一致箇所の開始位置
+ * @param length - 一致した文字数
+ * @returns 抜粋（前後を省略した場合は省略記号を付与）
+ */
+export function extractSnippet(text: string, index: number, length: number): string {
+  const start = Math.max(0, index - SNIPPET_CONTEXT_LENGTH);
+  const end = Math.min(text.length, index + length + SNIPPET_CONTEXT_LENGTH);
+  const prefix = start > 0 ? ELLIPSIS : "";
+  const suffix = end < text.length ? ELLIPSIS : "";
+  return `${prefix}${text.slice(start, end).trim()}${suffix}`;
+}
+
+/**
+ * Todoの検索キーワード一致箇所のハイライトを作成する
+ * 説明に一致した場合は一致箇所周辺の抜粋、タイトルのみに一致した場合はタイトルを返す
+ * 検索条件と同じく大文字小文字は区別しない
+ * @param todo - タイトルと説明
+ * @param q - 検索キーワード
+ * @returns ハイライト、一致しない場合はnull
+ */
+export function buildHighlight(
+  todo: { title: string; description: string | null },
+  q: string,
+): TodoHighlight | null {
+  const keyword = q.toLowerCase();
+
+  if (todo.description) {
+    const index = todo.description.toLowerCase().indexOf(keyword);
+    if (index !== -1) {
+      return {
+        field: "description",
+        snippet: extractSnippet(todo.description, index, keyword.length),
+      };
+    }
+  }
+
+  const index = todo.title.toLowerCase().indexOf(keyword);
+  if (index !== -1) {
+    return { field: "title", snippet: extractSnippet(todo.title, index, keyword.length) };
+  }
+
+  return null;
+}
@@ -0,0 +1,32 @@
+/**
+ * Todo作成の冪等キーの定期クリーンアップ
+ * @module features/todo/idempotency-key-cleanup
+ */
+
+import type { IdempotencyKeyRepositoryInterface } from "./idempotency-key-repository";
+
+/**
+ * 有効期限切れの冪等キーを定期的に削除する
+ * @param repository - 冪等キーリポジトリ
+ * @param intervalMs - 実行間隔（ミリ秒）
+ * @returns クリーンアップを停止する関数
+ */
+export function startIdempotencyKeyCleanup(
+  repository: IdempotencyKeyRepositoryInterface,
+  intervalMs: number,
+): () => void {
+  const run = async () => {
+    try {
+      const removed = await repository.cleanupExpired();
+      console.log(`Idempotency key cleanup: removed ${removed} expired entries`);
+    } catch (err) {
+      console.error("Idempotency key cleanup failed:", err);
+    }
+  };
+
+  const timer = setInterval(run, intervalMs);
+  // タイマーだけが残っている場合はプロセスの終了を妨げない
+  timer.unref();
+
+  return () => clearInterval(timer);
+}
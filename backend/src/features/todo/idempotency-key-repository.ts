@@ -0,0 +1,127 @@
+/**
+ * 冪等キーリポジトリ
+ * @module features/todo/idempotency-key-repository
+ */
+
+import { and, eq, gt, lte } from "drizzle-orm";
+import type { DatabaseOrTransaction } from "../../lib/db";
+import { type IdempotencyKey, idempotencyKeys } from "../../models/schema";
+
+/**
+ * 冪等キーリポジトリのインターフェース
+ */
+export interface IdempotencyKeyRepositoryInterface {
+  /**
+   * 有効期限内の冪等キーを取得する
+   * @param userId - ユーザーID
+   * @param key - 冪等キー
+   * @param now - 基準日時（デフォルト: 現在日時）
+   * @returns 冪等キー（存在しないか期限切れの場合はnull）
+   */
+  findActive(userId: number, key: string, now?: Date): Promise<IdempotencyKey | null>;
+
+  /**
+   * 冪等キーを登録する
+   * 期限切れの同一キーが残っている場合は上書きする
+   * @param userId - ユーザーID
+   * @param key - 冪等キー
+   * @param todoId - 作成したTodoのID
+   * @param requestHash - リクエスト内容のハッシュ
+   * @param expiresAt - 有効期限
+   * @returns 登録できた場合はtrue（有効な同一キーが既に存在する場合はfalse）
+   */
+  create(
+    userId: number,
+    key: string,
+    todoId: number,
+    requestHash: string,
+    expiresAt: Date,
+  ): Promise<boolean>;
+
+  /**
+   * 有効期限切れの冪等キーを削除する
+   * @param now - 基準日時（デフォルト: 現在日時）
+   * @returns 削除した件数
+   */
+  cleanupExpired(now?: Date): Promise<number>;
+}
+
+/**
+ * 冪等キーリポジトリの実装
+ * Todo作成リクエストの Idempotency-Key と作成結果の対応を管理する
+ */
+export class IdempotencyKeyRepository implements IdempotencyKeyRepositoryInterface {
+  /**
+   * IdempotencyKeyRepositoryを作成する
+   * @param db - Drizzleデータベースインスタンスまたはトランザクション
+   */
+  constructor(private db: DatabaseOrTransaction) {}
+
+  /**
+   * 有効期限内の冪等キーを取得する
+   * @param userId - ユーザーID
+   * @param key - 冪等キー
+   * @param now - 基準日時（デフォルト: 現在日時）
+   * @returns 冪等キー（存在しないか期限切れの場合はnull）
+   */
+  async findActive(
+    userId: number,
+    key: string,
+    now: Date = new Date(),
+  ): Promise<IdempotencyKey | null> {
+    const [result] = await this.db
+      .select()
+      .from(idempotencyKeys)
+      .where(
+        and(
+          eq(idempotencyKeys.userId, userId),
+          eq(idempotencyKeys.key, key),
+          gt(idempotencyKeys.expiresAt, now),
+        ),
+      )
+      .limit(1);
+    return result ?? null;
+  }
+
+  /**
+   * 冪等キーを登録する
+   * 期限切れの同一キーが残っている場合は上書きする
+   * @param userId - ユーザーID
+   * @param key - 冪等キー
+   * @param todoId - 作成したTodoのID
+   * @param requestHash - リクエスト内容のハッシュ
+   * @param expiresAt - 有効期限
+   * @returns 登録できた場合はtrue（有効な同一キーが既に存在する場合はfalse）
+   */
+  async create(
+    userId: number,
+    key: string,
+    todoId: number,
+    requestHash: string,
+    expiresAt: Date,
+  ): Promise<boolean> {
+    const inserted = await this.db
+      .insert(idempotencyKeys)
+      .values({ userId, key, todoId, requestHash, expiresAt })
+      .onConflictDoUpdate({
+        target: [idempotencyKeys.userId, idempotencyKeys.key],
+        set: { todoId, requestHash, expiresAt, createdAt: new Date() },
+        setWhere: lte(idempotencyKeys.expiresAt, new Date()),
+      })
+      .returning({ id: idempotencyKeys.id });
+    return inserted.length > 0;
+  }
+
+  /**
+   * 有効期限切れの冪等キーを削除する
+   * @param now - 基準日時（デフォルト: 現在日時）
+   * @returns 削除した件数
+   */
+  async cleanupExpired(now: Date = new Date()): Promise<number> {
+    const deleted = await this.db
+      .delete(idempotencyKeys)
+      .where(lte(idempotencyKeys.expiresAt, now))
+      .returning({ id: idempotencyKeys.id });
+    return deleted.length;
+  }
+}
@@ -5,12 +5,31 @@
 
 import { zValidator } from "@hono/zod-validator";
 import { Hono } from "hono";
+import { TODO } from "../../lib/constants";
 import { getTodoSearchService, getTodoService } from "../../lib/container";
-import { created, noContent, ok } from "../../lib/response";
+import { preconditionFailed } from "../../lib/errors";
+import { bodyETag, created, envelope, noContent, ok, okWithETag } from "../../lib/response";
 import { handleValidationError } from "../../lib/validator";
+import { TODO_ERROR_MESSAGES } from "../../shared/errors/messages";
 import { getCurrentUser, jwtAuth } from "../../shared/middleware/auth";
+import { getTimestampVariant } from "../../shared/middleware/timestamp-format";
 import { normalizeSearchParams, searchTodoSchema } from "./search-validators";
-import { createTodoSchema, idParamSchema, updateOrderSchema, updateTodoSchema } from "./validators";
+import {
+  agendaQuerySchema,
+  batchQuerySchema,
+  bulkTagsSchema,
+  createTodoHeaderSchema,
+  createTodoSchema,
+  idParamSchema,
+  listTodoQuerySchema,
+  moveTodoSchema,
+  showTodoQuerySchema,
+  snoozeTodoSchema,
+  trendsQuerySchema,
+  updateOrderSchema,
+  updateTodoHeaderSchema,
+  updateTodoSchema,
+} from "./validators";
 
 const todos = new Hono();
 
@@ -20,12 +39,26 @@ todos.use("*", jwtAuth());
 /**
  * Todo一覧を取得
  * GET /api/v1/todos
+ * スヌーズ中のTodoは include_snoozed=true の場合のみ含める
+ * archived=true の場合はアーカイブ済みのTodoを返す
+ * envelope=true の場合は {data, meta} 形式で返す
+ * HEAD の場合は一覧を取得せず、件数のみを X-Total-Count ヘッダーで返す
  */
-todos.get("/", async (c) => {
+todos.get("/", zValidator("query", listTodoQuerySchema, handleValidationError()), async (c) => {
   const user = getCurrentUser(c);
+  const query = c.req.valid("query");
   const todoService = getTodoService();
-  const result = await todoService.list(user.id);
-  return ok(c, result);
+  const options = { includeSnoozed: query.include_snoozed, archived: query.archived };
+
+  // HonoはHEADをGETのハンドラで処理してボディを破棄するため、ここで件数のみを返す
+  if (c.req.method === "HEAD") {
+    const count = await todoService.count(user.id, options);
+    c.header(TODO.TOTAL_COUNT_HEADER, String(count));
+    return c.body(null, 200);
+  }
+
+  const result = await todoService.list(user.id, options);
+  return ok(c, query.envelope ? envelope(result) : result);
 });
 
 /**
@@ -43,29 +76,114 @@ todos.get("/search", zValidator("query", searchTodoSchema, handleValidationError
 });
 
 /**
- * Todo詳細を取得
- * GET /api/v1/todos/:id
+ * 今日のアジェンダを取得
+ * GET /api/v1/todos/agenda
+ * 期限切れ・今日が期限のTodoを返す。tz で「今日」の基準となるタイムゾーンを指定できる
+ * 注意: /:id より前に定義する必要がある
  */
-todos.get("/:id", zValidator("param", idParamSchema, handleValidationError()), async (c) => {
+todos.get("/agenda", zValidator("query", agendaQuerySchema, handleValidationError()), async (c) => {
   const user = getCurrentUser(c);
-  const { id } = c.req.valid("param");
+  const query = c.req.valid("query");
   const todoService = getTodoService();
-  const result = await todoService.show(id, user.id);
+  const result = await todoService.agenda(user.id, query.tz, {
+    includeCompleted: query.include_completed,
+    includeSnoozed: query.include_snoozed,
+  });
   return ok(c, result);
 });
 
 /**
- * Todoを作成
- * POST /api/v1/todos
+ * 作成数・完了数の推移を取得
+ * GET /api/v1/todos/trends?from=2024-01-01&to=2024-01-31&bucket=day
+ * 期間内の集計単位（日・週）ごとの件数を返す。tz で日付を判定するタイムゾーンを指定できる
+ * 注意: /:id より前に定義する必要がある
  */
-todos.post("/", zValidator("json", createTodoSchema, handleValidationError()), async (c) => {
+todos.get("/trends", zValidator("query", trendsQuerySchema, handleValidationError()), async (c) => {
   const user = getCurrentUser(c);
-  const body = c.req.valid("json");
+  const query = c.req.valid("query");
   const todoService = getTodoService();
-  const result = await todoService.create(body, user.id);
-  return created(c, result);
+  const result = await todoService.trends(user.id, {
+    from: query.from,
+    to: query.to,
+    bucket: query.bucket,
+    timeZone: query.tz,
+  });
+  return ok(c, result);
 });
 
+/**
+ * 指定したIDのTodoを一括取得
+ * GET /api/v1/todos/batch?ids=1,2,3
+ * 指定した順に返し、存在しないIDや他ユーザーのTodoのIDは除外する
+ * 注意: /:id より前に定義する必要がある
+ */
+todos.get("/batch", zValidator("query", batchQuerySchema, handleValidationError()), async (c) => {
+  const user = getCurrentUser(c);
+  const { ids } = c.req.valid("query");
+  const todoService = getTodoService();
+  const result = await todoService.batch(ids, user.id);
+  return ok(c, result);
+});
+
+/**
+ * Todo詳細を取得
+ * GET /api/v1/todos/:id
+ * レスポンス本文から弱いETagを付与し、If-None-Match が一致する場合は304を返す
+ * タグ・カテゴリ・ラベルの変更はTodoの更新日時を変えないため、更新日時ではなく本文で判定する
+ * 更新時の If-Unmodified-Since に使えるよう Last-Modified も付与する
+ * context=position の場合は一覧で前後に並ぶTodoのID（prev_id / next_id）を含める。
+ * 前後のTodoは他のTodoの変更で変わるため、この場合はETagを付与しない
+ */
+todos.get(
+  "/:id",
+  zValidator("param", idParamSchema, handleValidationError()),
+  zValidator("query", showTodoQuerySchema, handleValidationError()),
+  async (c) => {
+    const user = getCurrentUser(c);
+    const { id } = c.req.valid("param");
+    const { context } = c.req.valid("query");
+    const todoService = getTodoService();
+
+    if (context === "position") {
+      const result = await todoService.showWithNeighbors(id, user.id);
+      return ok(c, result);
+    }
+
+    const result = await todoService.show(id, user.id);
+    c.header("Last-Modified", new Date(result.updated_at).toUTCString());
+    return okWithETag(c, result, bodyETag(result, getTimestampVariant(c)));
+  },
+);
+
+/**
+ * Todoを作成
+ * POST /api/v1/todos
+ * Idempotency-Key ヘッダー指定時、有効期限内の同じキーでの再送には作成済みのTodoを返し、
+ * Idempotent-Replayed: true ヘッダーを付与する
+ */
+todos.post(
+  "/",
+  zValidator("header", createTodoHeaderSchema, handleValidationError()),
+  zValidator("json", createTodoSchema, handleValidationError()),
+  async (c) => {
+    const user = getCurrentUser(c);
+    const body = c.req.valid("json");
+    const idempotencyKey = c.req.valid("header")["idempotency-key"];
+    const todoService = getTodoService();
+
+    if (!idempotencyKey) {
+      const result = await todoService.create(body, user.id);
+      return created(c, result);
+    }
+
+    const { todo, replayed } = await todoService.createIdempotent(body, user.id, idempotencyKey);
+    if (replayed) {
+      c.header(TODO.IDEMPOTENT_REPLAYED_HEADER, "true");
+    }
+    return created(c, todo);
+  },
+);
+
 /**
  * Todoの順序を一括更新
  * PATCH /api/v1/todos/update_order
@@ -83,24 +201,139 @@ todos.patch(
   },
 );
 
+/**
+ * 複数のTodoにタグを一括で付け外し
+ * POST /api/v1/todos/bulk_tags
+ */
+todos.post("/bulk_tags", zValidator("json", bulkTagsSchema, handleValidationError()), async (c) => {
+  const user = getCurrentUser(c);
+  const body = c.req.valid("json");
+  const todoService = getTodoService();
+  const result = await todoService.bulkUpdateTags(body, user.id);
+  return ok(c, result);
+});
+
 /**
  * Todoを更新
  * PATCH /api/v1/todos/:id
+ * If-Unmodified-Since 指定時、その日時より後に更新されていれば更新せず412を返す
  */
 todos.patch(
   "/:id",
   zValidator("param", idParamSchema, handleValidationError()),
+  zValidator("header", updateTodoHeaderSchema, handleValidationError()),
   zValidator("json", updateTodoSchema, handleValidationError()),
   async (c) => {
     const user = getCurrentUser(c);
     const { id } = c.req.valid("param");
+    const ifUnmodifiedSince = c.req.valid("header")["if-unmodified-since"];
     const body = c.req.valid("json");
     const todoService = getTodoService();
+
+    if (ifUnmodifiedSince) {
+      const current = await todoService.show(id, user.id);
+      // HTTP-date は秒精度のため、updated_at も秒単位に切り捨てて比較する
+      const updatedAt = Math.floor(Date.parse(current.updated_at) / 1000) * 1000;
+      if (updatedAt > ifUnmodifiedSince.getTime()) {
+        throw preconditionFailed(TODO_ERROR_MESSAGES.MODIFIED_SINCE);
+      }
+    }
+
     const result = await todoService.update(id, body, user.id);
+    c.header("Last-Modified", new Date(result.updated_at).toUTCString());
     return ok(c, result);
   },
 );
 
+/**
+ * Todoを別のTodoの前後に移動
+ * POST /api/v1/todos/:id/move
+ */
+todos.post(
+  "/:id/move",
+  zValidator("param", idParamSchema, handleValidationError()),
+  zValidator("json", moveTodoSchema, handleValidationError()),
+  async (c) => {
+    const user = getCurrentUser(c);
+    const { id } = c.req.valid("param");
+    const body = c.req.valid("json");
+    const todoService = getTodoService();
+    const result = await todoService.move(id, body, user.id);
+    return ok(c, result);
+  },
+);
+
+/**
+ * Todoをスヌーズ
+ * POST /api/v1/todos/:id/snooze
+ */
+todos.post(
+  "/:id/snooze",
+  zValidator("param", idParamSchema, handleValidationError()),
+  zValidator("json", snoozeTodoSchema, handleValidationError()),
+  async (c) => {
+    const user = getCurrentUser(c);
+    const { id } = c.req.valid("param");
+    const body = c.req.valid("json");
+    const todoService = getTodoService();
+    const result = await todoService.snooze(id, body, user.id);
+    return ok(c, result);
+  },
+);
+
+/**
+ * Todoにスターを付ける
+ * POST /api/v1/todos/:id/star
+ */
+todos.post("/:id/star", zValidator("param", idParamSchema, handleValidationError()), async (c) => {
+  const user = getCurrentUser(c);
+  const { id } = c.req.valid("param");
+  const todoService = getTodoService();
+  const result = await todoService.setStarred(id, true, user.id);
+  return ok(c, result);
+});
+
+/**
+ * Todoのスターを外す
+ * DELETE /api/v1/todos/:id/star
+ */
+todos.delete(
+  "/:id/star",
+  zValidator("param", idParamSchema, handleValidationError()),
+  async (c) => {
+    const user = getCurrentUser(c);
+    const { id } = c.req.valid("param");
+    const todoService = getTodoService();
+    const result = await todoService.setStarred(id, false, user.id);
+    return ok(c, result);
+  },
+);
+
+/**
+ * 完了済みのTodoをすべてアーカイブ
+ * POST /api/v1/todos/archive_completed
+ * 未アーカイブの完了済みTodoの archived_at を設定し、アーカイブした件数を返す
+ */
+todos.post("/archive_completed", async (c) => {
+  const user = getCurrentUser(c);
+  const todoService = getTodoService();
+  const result = await todoService.archiveCompleted(user.id);
+  return ok(c, result);
+});
+
+/**
+ * 完了済みのTodoをすべて削除
+ * DELETE /api/v1/todos/completed
+ * 削除した件数を返す。削除対象がない場合も deleted_count: 0 で成功とする
+ * 注意: /:id より前に定義する必要がある
+ */
+todos.delete("/completed", async (c) => {
+  const user = getCurrentUser(c);
+  const todoService = getTodoService();
+  const result = await todoService.destroyCompleted(user.id);
+  return ok(c, result);
+});
+
 /**
  * Todoを削除
  * DELETE /api/v1/todos/:id
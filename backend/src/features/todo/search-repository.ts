@@ -6,31 +6,54 @@
 import {
   and,
   asc,
+  type Column,
   count,
   desc,
   eq,
+  exists,
   gte,
   ilike,
   inArray,
   isNull,
+  lt,
   lte,
+  notExists,
   or,
   sql,
   type SQL,
 } from "drizzle-orm";
-import { TODO } from "../../lib/constants";
+import { ATTACHABLE_TYPES, CATEGORY, COMMENTABLE_TYPES, TODO } from "../../lib/constants";
+import { todayIn } from "../../lib/date";
 import type { DatabaseOrTransaction } from "../../lib/db";
 import {
   type Category,
   categories,
+  comments,
+  files,
+  type Label,
+  labels,
   type Tag,
   tags,
+  todoLabels,
   todos,
   todoTags,
 } from "../../models/schema";
+import { archivedCondition, notSnoozedCondition } from "./conditions";
 import type { NormalizedSearchParams } from "./search-validators";
 import type { TodoWithRelations } from "./types";
 
+/**
+ * ファセット集計結果（ページネーション前の絞り込み結果全体に対する件数）
+ */
+export interface FacetCounts {
+  /** ステータス（整数値）ごとの件数 */
+  status: Array<{ value: number; count: number }>;
+  /** 優先度（整数値）ごとの件数 */
+  priority: Array<{ value: number; count: number }>;
+  /** カテゴリIDごとの件数（nullはカテゴリなし） */
+  category: Array<{ categoryId: number | null; count: number }>;
+}
+
 /**
  * 検索結果
  */
@@ -39,8 +62,13 @@ export interface SearchResult {
   todos: TodoWithRelations[];
   /** トータル件数 */
   total: number;
+  /** ファセット集計 */
+  facets: FacetCounts;
 }
 
+/** 該当なしの場合のファセット集計 */
+const EMPTY_FACETS: FacetCounts = { status: [], priority: [], category: [] };
+
 /**
  * 検索リポジトリのインターフェース
  */
@@ -71,25 +99,13 @@ export class TodoSearchRepository implements TodoSearchRepositoryInterface {
    * @returns 検索結果とトータル件数
    */
   async search(userId: number, params: NormalizedSearchParams): Promise<SearchResult> {
-    // WHERE条件を構築
-    const whereConditions = this.buildWhereConditions(userId, params);
-
-    // タグフィルターがある場合、対象TodoのIDを先に取得
-    let targetTodoIds: number[] | undefined;
-    if (params.tagIds && params.tagIds.length > 0) {
-      targetTodoIds = await this.getTodoIdsByTags(userId, params.tagIds, params.tagMode);
+    const finalConditions = await this.buildFilterConditions(userId, params);
 
-      // タグに一致するTodoがない場合は空結果を返す
-      if (targetTodoIds.length === 0) {
-        return { todos: [], total: 0 };
-      }
+    // タグに一致するTodoがない場合は空結果を返す
+    if (finalConditions === null) {
+      return { todos: [], total: 0, facets: EMPTY_FACETS };
     }
 
-    // タグフィルター条件を追加
-    const finalConditions = targetTodoIds
-      ? and(whereConditions, inArray(todos.id, targetTodoIds))
-      : whereConditions;
-
     // トータル件数を取得
     const totalResult = await this.db
       .select({ count: count() })
@@ -98,9 +114,12 @@ export class TodoSearchRepository implements TodoSearchRepositoryInterface {
     const total = totalResult[0]?.count ?? 0;
 
     if (total === 0) {
-      return { todos: [], total: 0 };
+      return { todos: [], total: 0, facets: EMPTY_FACETS };
     }
 
+    // ファセットを集計（ページネーション前の全件が対象）
+    const facets = await this.countFacets(finalConditions);
+
     // ソート条件を構築
     const orderByClause = this.buildOrderByClause(params);
 
@@ -117,13 +136,67 @@ export class TodoSearchRepository implements TodoSearchRepositoryInterface {
       .offset(offset);
 
     if (todoList.length === 0) {
-      return { todos: [], total };
+      return { todos: [], total, facets };
     }
 
     // リレーションを取得して結合
     const todosWithRelations = await this.fetchRelations(todoList);
 
-    return { todos: todosWithRelations, total };
+    return { todos: todosWithRelations, total, facets };
+  }
+
+  /**
+   * 検索パラメータから最終的な絞り込み条件を構築する
+   * @param userId - ユーザーID
+   * @param params - 検索パラメータ
+   * @returns SQL条件（タグフィルターに一致するTodoがない場合はnull）
+   */
+  private async buildFilterConditions(
+    userId: number,
+    params: NormalizedSearchParams,
+  ): Promise<SQL | undefined | null> {
+    // WHERE条件を構築
+    const whereConditions = this.buildWhereConditions(userId, params);
+
+    // タグフィルターがない場合はそのまま使用
+    if (!params.tagIds || params.tagIds.length === 0) {
+      return whereConditions;
+    }
+
+    // タグフィルターがある場合、対象TodoのIDを先に取得
+    const targetTodoIds = await this.getTodoIdsByTags(userId, params.tagIds, params.tagMode);
+    if (targetTodoIds.length === 0) {
+      return null;
+    }
+
+    return and(whereConditions, inArray(todos.id, targetTodoIds));
+  }
+
+  /**
+   * ステータス・優先度・カテゴリごとの件数を集計する
+   * @param conditions - 検索と同じ絞り込み条件
+   * @returns ファセット集計結果
+   */
+  private async countFacets(conditions: SQL | undefined): Promise<FacetCounts> {
+    const [status, priority, category] = await Promise.all([
+      this.db
+        .select({ value: todos.status, count: count() })
+        .from(todos)
+        .where(conditions)
+        .groupBy(todos.status),
+      this.db
+        .select({ value: todos.priority, count: count() })
+        .from(todos)
+        .where(conditions)
+        .groupBy(todos.priority),
+      this.db
+        .select({ categoryId: todos.categoryId, count: count() })
+        .from(todos)
+        .where(conditions)
+        .groupBy(todos.categoryId),
+    ]);
+
+    return { status, priority, category };
   }
 
   /**
@@ -133,7 +206,7 @@ export class TodoSearchRepository implements TodoSearchRepositoryInterface {
    * @returns SQL条件
    */
   private buildWhereConditions(userId: number, params: NormalizedSearchParams): SQL | undefined {
-    const conditions: SQL[] = [eq(todos.userId, userId)];
+    const conditions: SQL[] = [eq(todos.userId, userId), archivedCondition(params.archived)];
 
     // テキスト検索（title, description のILIKE）
     if (params.q) {
@@ -147,9 +220,62 @@ export class TodoSearchRepository implements TodoSearchRepositoryInterface {
       }
     }
 
+    // 添付ファイル名検索（ファイル名に部分一致する添付を持つTodo）
+    if (params.fileName) {
+      conditions.push(
+        exists(
+          this.db
+            .select({ id: files.id })
+            .from(files)
+            .where(
+              and(
+                eq(files.userId, userId),
+                eq(files.attachableType, ATTACHABLE_TYPES.TODO),
+                eq(files.attachableId, todos.id),
+                ilike(files.filename, `%${params.fileName}%`),
+              ),
+            ),
+        ),
+      );
+    }
+
+    // 添付ファイルの有無フィルター
+    if (params.hasFiles !== undefined) {
+      const attachedFiles = this.db
+        .select({ id: files.id })
+        .from(files)
+        .where(
+          and(
+            eq(files.userId, userId),
+            eq(files.attachableType, ATTACHABLE_TYPES.TODO),
+            eq(files.attachableId, todos.id),
+          ),
+        );
+      conditions.push(params.hasFiles ? exists(attachedFiles) : notExists(attachedFiles));
+    }
+
+    // コメント投稿者フィルター（削除済みでないコメントを指定ユーザーが投稿したTodo）
+    if (params.commentedBy !== undefined) {
+      conditions.push(
+        exists(
+          this.db
+            .select({ id: comments.id })
+            .from(comments)
+            .where(
+              and(
+                eq(comments.userId, params.commentedBy),
+                eq(comments.commentableType, COMMENTABLE_TYPES.TODO),
+                eq(comments.commentableId, todos.id),
+                isNull(comments.deletedAt),
+              ),
+            ),
+        ),
+      );
+    }
+
     // カテゴリフィルター
     if (params.categoryId !== undefined) {
-      if (params.categoryId === -1) {
+      if (params.categoryId === CATEGORY.UNCATEGORIZED_ID) {
         // カテゴリなし
         conditions.push(isNull(todos.categoryId));
       } else {
@@ -170,6 +296,11 @@ export class TodoSearchRepository implements TodoSearchRepositoryInterface {
       conditions.push(inArray(todos.priority, priorityValues));
     }
 
+    // スターフィルター
+    if (params.starred !== undefined) {
+      conditions.push(eq(todos.starred, params.starred));
+    }
+
     // 日付範囲フィルター
     if (params.dueDateFrom) {
       conditions.push(gte(todos.dueDate, params.dueDateFrom));
@@ -178,9 +309,39 @@ export class TodoSearchRepository implements TodoSearchRepositoryInterface {
       conditions.push(lte(todos.dueDate, params.dueDateTo));
     }
 
+    // 完了日・作成日・更新日の範囲フィルター
+    conditions.push(
+      ...this.buildDateRangeConditions(todos.completedAt, params.completedFrom, params.completedTo),
+      ...this.buildDateRangeConditions(todos.createdAt, params.createdFrom, params.createdTo),
+      ...this.buildDateRangeConditions(todos.updatedAt, params.updatedFrom, params.updatedTo),
+    );
+
+    // スヌーズ中のTodoはデフォルトで除外
+    if (!params.includeSnoozed) {
+      conditions.push(notSnoozedCondition(todayIn()));
+    }
+
     return and(...conditions);
   }
 
+  /**
+   * 日時カラムに対する日付範囲の条件を構築する（終了日は当日中を含む）
+   * @param column - 日時カラム
+   * @param from - 開始日（YYYY-MM-DD）
+   * @param to - 終了日（YYYY-MM-DD）
+   * @returns SQL条件の配列
+   */
+  private buildDateRangeConditions(column: Column, from?: string, to?: string): SQL[] {
+    const conditions: SQL[] = [];
+    if (from) {
+      conditions.push(gte(column, sql`${from}::date`));
+    }
+    if (to) {
+      conditions.push(lt(column, sql`${to}::date + 1`));
+    }
+    return conditions;
+  }
+
   /**
    * タグフィルターに一致するTodoのIDを取得する
    * @param userId - ユーザーID
@@ -215,11 +376,21 @@ export class TodoSearchRepository implements TodoSearchRepositoryInterface {
   }
 
   /**
-   * ソート条件を構築する
+   * ソート条件を構築する（ピン留めしたTodoを先頭にする）
    * @param params - 検索パラメータ
    * @returns ソート条件の配列
    */
   private buildOrderByClause(params: NormalizedSearchParams): SQL[] {
+    // ピン留めしたTodoはソート条件に関わらず先頭に並べる
+    return [desc(todos.pinned), ...this.buildSortClause(params)];
+  }
+
+  /**
+   * 指定されたソートフィールド・ソート順の条件を構築する
+   * @param params - 検索パラメータ
+   * @returns ソート条件の配列
+   */
+  private buildSortClause(params: NormalizedSearchParams): SQL[] {
     const direction = params.sortOrder === "desc" ? desc : asc;
 
     switch (params.sortBy) {
@@ -236,6 +407,16 @@ export class TodoSearchRepository implements TodoSearchRepositoryInterface {
         return [direction(todos.priority), asc(todos.position)];
       case "status":
         return [direction(todos.status), asc(todos.position)];
+      case "completed_at":
+        // NULL（未完了）を最後に配置
+        return [
+          sql`${todos.completedAt} IS NULL`,
+          direction(todos.completedAt),
+          asc(todos.position),
+        ];
+      case "relevance":
+        // キーワードとの一致度の高い順（sort_orderに関わらず降順）
+        return [desc(this.buildRelevanceRank(params.q ?? "")), desc(todos.createdAt)];
       case "position":
       default:
         return [direction(todos.position)];
@@ -243,7 +424,18 @@ export class TodoSearchRepository implements TodoSearchRepositoryInterface {
   }
 
   /**
-   * Todoのリレーション（カテゴリ、タグ）を取得する
+   * キーワードとの一致度（ts_rank）を計算するSQLを構築する
+   * title と description を結合した文書に対して全文検索のランクを計算する
+   * @param q - 検索キーワード
+   * @returns ランク値のSQL
+   */
+  private buildRelevanceRank(q: string): SQL {
+    const document = sql`${todos.title} || ' ' || coalesce(${todos.description}, '')`;
+    return sql`ts_rank(to_tsvector('simple', ${document}), plainto_tsquery('simple', ${q}))`;
+  }
+
+  /**
+   * Todoのリレーション（カテゴリ、タグ、ラベル）を取得する
    * @param todoList - Todoの配列
    * @returns TodoWithRelationsの配列
    */
@@ -285,11 +477,29 @@ export class TodoSearchRepository implements TodoSearchRepositoryInterface {
       tagsMap.set(row.todoId, existing);
     }
 
+    // ラベルを取得
+    const labelResults = await this.db
+      .select({
+        todoId: todoLabels.todoId,
+        label: labels,
+      })
+      .from(todoLabels)
+      .innerJoin(labels, eq(todoLabels.labelId, labels.id))
+      .where(inArray(todoLabels.todoId, todoIds));
+
+    const labelsMap = new Map<number, Label[]>();
+    for (const row of labelResults) {
+      const existing = labelsMap.get(row.todoId) ?? [];
+      existing.push(row.label);
+      labelsMap.set(row.todoId, existing);
+    }
+
     // 結果を組み立て
     return todoList.map((todo) => ({
       todo,
       category: todo.categoryId ? (categoryMap.get(todo.categoryId) ?? null) : null,
       tags: tagsMap.get(todo.id) ?? [],
+      labels: labelsMap.get(todo.id) ?? [],
     }));
   }
 }
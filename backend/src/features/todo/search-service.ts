@@ -3,10 +3,22 @@
  * @module features/todo/search-service
  */
 
-import type { TodoSearchRepositoryInterface } from "./search-repository";
+import { CATEGORY } from "../../lib/constants";
+import { validationError } from "../../lib/errors";
+import { TODO_ERROR_MESSAGES } from "../../shared/errors/messages";
+import type { UserRepositoryInterface } from "../auth/user-repository";
+import { buildHighlight, type TodoHighlight } from "./highlight";
+import type { FacetCounts, TodoSearchRepositoryInterface } from "./search-repository";
 import type { NormalizedSearchParams } from "./search-validators";
-import { formatTodoResponse } from "./types";
-import type { TodoResponse } from "../../shared/validators/responses";
+import type { TodoTagValidatorRepositoryInterface } from "./todo-tag-validator-repository";
+import {
+  formatTagRef,
+  formatTodoResponse,
+  type PriorityColors,
+  priorityToString,
+  statusToString,
+} from "./types";
+import type { TagRef, TodoResponse } from "../../shared/validators/responses";
 
 /**
  * フィルター適用状態
@@ -14,6 +26,12 @@ import type { TodoResponse } from "../../shared/validators/responses";
 export interface FiltersApplied {
   /** 検索クエリ */
   q?: string;
+  /** 添付ファイル名 */
+  file_name?: string;
+  /** 添付ファイルの有無フィルター */
+  has_files?: boolean;
+  /** コメント投稿者のユーザーID */
+  commented_by?: number;
   /** ステータスフィルター */
   status?: string[];
   /** 優先度フィルター */
@@ -22,12 +40,45 @@ export interface FiltersApplied {
   category_id?: number | null;
   /** タグID */
   tag_ids?: number[];
+  /** タグ（tag_ids のうちユーザーが所有するもの） */
+  tags?: TagRef[];
   /** タグマッチモード */
   tag_mode?: string;
   /** 期限開始日 */
   due_date_from?: string;
   /** 期限終了日 */
   due_date_to?: string;
+  /** 完了開始日 */
+  completed_from?: string;
+  /** 完了終了日 */
+  completed_to?: string;
+  /** 作成開始日 */
+  created_from?: string;
+  /** 作成終了日 */
+  created_to?: string;
+  /** 更新開始日 */
+  updated_from?: string;
+  /** 更新終了日 */
+  updated_to?: string;
+  /** スヌーズ中のTodoを含めるか */
+  include_snoozed?: boolean;
+  /** アーカイブ済みのTodoを対象にしたか */
+  archived?: boolean;
+  /** スターフィルター */
+  starred?: boolean;
+}
+
+/**
+ * 検索ファセット
+ * 絞り込み後・ページネーション前の結果全体に対する件数
+ */
+export interface SearchFacets {
+  /** ステータスごとの件数 */
+  status: Record<"pending" | "in_progress" | "completed", number>;
+  /** 優先度ごとの件数 */
+  priority: Record<"low" | "medium" | "high", number>;
+  /** カテゴリごとの件数（category_id: nullはカテゴリなし） */
+  category: Array<{ category_id: number | null; count: number }>;
 }
 
 /**
@@ -46,6 +97,8 @@ export interface SearchMeta {
   search_query?: string;
   /** 適用されたフィルター */
   filters_applied: FiltersApplied;
+  /** ファセット */
+  facets: SearchFacets;
 }
 
 /**
@@ -60,12 +113,21 @@ export interface SearchSuggestion {
   current_filters?: string[];
 }
 
+/**
+ * 検索結果のTodo
+ * 検索キーワード指定時のみ highlight を含む
+ */
+export type TodoSearchResult = TodoResponse & {
+  /** キーワード一致箇所のハイライト */
+  highlight?: TodoHighlight | null;
+};
+
 /**
  * 検索レスポンス
  */
 export interface TodoSearchResponse {
   /** Todoデータ */
-  data: TodoResponse[];
+  data: TodoSearchResult[];
   /** メタデータ */
   meta: SearchMeta;
   /** サジェスション（結果0件時） */
@@ -79,24 +141,41 @@ export class TodoSearchService {
   /**
    * TodoSearchServiceを作成する
    * @param searchRepository - 検索リポジトリ
+   * @param tagRepository - タグ検証リポジトリ
+   * @param userRepository - ユーザーリポジトリ（コメント投稿者の存在確認に使用）
+   * @param priorityColors - カテゴリ未設定のTodoに使う優先度ごとの既定の色
    */
-  constructor(private searchRepository: TodoSearchRepositoryInterface) {}
+  constructor(
+    private searchRepository: TodoSearchRepositoryInterface,
+    private tagRepository: TodoTagValidatorRepositoryInterface,
+    private userRepository: UserRepositoryInterface,
+    private priorityColors: PriorityColors,
+  ) {}
 
   /**
    * Todoを検索する
    * @param params - 正規化された検索パラメータ
    * @param userId - ユーザーID
    * @returns 検索レスポンス
+   * @throws ValidationError - commented_by に存在しないユーザーを指定した場合
    */
   async search(params: NormalizedSearchParams, userId: number): Promise<TodoSearchResponse> {
-    const { todos, total } = await this.searchRepository.search(userId, params);
+    if (params.commentedBy !== undefined) {
+      await this.validateCommentedBy(params.commentedBy);
+    }
 
-    // レスポンス形式に変換
-    const todoResponses: TodoResponse[] = todos.map(formatTodoResponse);
+    const { todos, total, facets } = await this.searchRepository.search(userId, params);
+
+    // レスポンス形式に変換（キーワード指定時はハイライトを付与）
+    const { q } = params;
+    const todoResponses: TodoSearchResult[] = todos.map((todo) => {
+      const response = formatTodoResponse(todo, this.priorityColors);
+      return q ? { ...response, highlight: buildHighlight(todo.todo, q) } : response;
+    });
 
     // メタデータを構築
     const totalPages = Math.ceil(total / params.perPage);
-    const filtersApplied = this.buildFiltersApplied(params);
+    const filtersApplied = await this.buildFiltersApplied(params, userId);
 
     // サジェスションを生成（結果が0件の場合）
     const suggestions = total === 0 ? this.generateSuggestions(params) : undefined;
@@ -110,22 +189,74 @@ export class TodoSearchService {
         per_page: params.perPage,
         search_query: params.q,
         filters_applied: filtersApplied,
+        facets: this.formatFacets(facets),
       },
       suggestions,
     };
   }
 
+  /**
+   * ファセット集計結果をレスポンス形式に変換する
+   * 該当0件のステータス・優先度も0として含める
+   * @param facets - ファセット集計結果
+   * @returns 検索ファセット
+   */
+  private formatFacets(facets: FacetCounts): SearchFacets {
+    const result: SearchFacets = {
+      status: { pending: 0, in_progress: 0, completed: 0 },
+      priority: { low: 0, medium: 0, high: 0 },
+      category: facets.category.map((c) => ({ category_id: c.categoryId, count: c.count })),
+    };
+
+    for (const { value, count } of facets.status) {
+      result.status[statusToString(value)] += count;
+    }
+    for (const { value, count } of facets.priority) {
+      result.priority[priorityToString(value)] += count;
+    }
+
+    return result;
+  }
+
+  /**
+   * コメント投稿者に指定したユーザーが存在するか検証する
+   * @param commentedBy - コメント投稿者のユーザーID
+   * @throws ValidationError - ユーザーが存在しない場合
+   */
+  private async validateCommentedBy(commentedBy: number): Promise<void> {
+    const user = await this.userRepository.findById(commentedBy);
+    if (!user) {
+      throw validationError(TODO_ERROR_MESSAGES.COMMENTED_BY_NOT_FOUND, {
+        commented_by: [TODO_ERROR_MESSAGES.COMMENTED_BY_NOT_FOUND],
+      });
+    }
+  }
+
   /**
    * 適用されたフィルターを構築する
+   * tag_ids はユーザーが所有するタグに解決して tags として含める（存在しないIDは省略）
    * @param params - 検索パラメータ
+   * @param userId - ユーザーID
    * @returns フィルター適用状態
    */
-  private buildFiltersApplied(params: NormalizedSearchParams): FiltersApplied {
+  private async buildFiltersApplied(
+    params: NormalizedSearchParams,
+    userId: number,
+  ): Promise<FiltersApplied> {
     const filters: FiltersApplied = {};
 
     if (params.q) {
       filters.q = params.q;
     }
+    if (params.fileName) {
+      filters.file_name = params.fileName;
+    }
+    if (params.hasFiles !== undefined) {
+      filters.has_files = params.hasFiles;
+    }
+    if (params.commentedBy !== undefined) {
+      filters.commented_by = params.commentedBy;
+    }
     if (params.status && params.status.length > 0) {
       filters.status = params.status;
     }
@@ -134,10 +265,14 @@ export class TodoSearchService {
     }
     if (params.categoryId !== undefined) {
       // -1はカテゴリなし（null）を表す
-      filters.category_id = params.categoryId === -1 ? null : params.categoryId;
+      filters.category_id =
+        params.categoryId === CATEGORY.UNCATEGORIZED_ID ? null : params.categoryId;
     }
     if (params.tagIds && params.tagIds.length > 0) {
       filters.tag_ids = params.tagIds;
+      filters.tags = (await this.tagRepository.findByIds(params.tagIds, userId))
+        .sort((a, b) => a.id - b.id)
+        .map(formatTagRef);
       filters.tag_mode = params.tagMode;
     }
     if (params.dueDateFrom) {
@@ -146,6 +281,33 @@ export class TodoSearchService {
     if (params.dueDateTo) {
       filters.due_date_to = params.dueDateTo;
     }
+    if (params.completedFrom) {
+      filters.completed_from = params.completedFrom;
+    }
+    if (params.completedTo) {
+      filters.completed_to = params.completedTo;
+    }
+    if (params.createdFrom) {
+      filters.created_from = params.createdFrom;
+    }
+    if (params.createdTo) {
+      filters.created_to = params.createdTo;
+    }
+    if (params.updatedFrom) {
+      filters.updated_from = params.updatedFrom;
+    }
+    if (params.updatedTo) {
+      filters.updated_to = params.updatedTo;
+    }
+    if (params.includeSnoozed) {
+      filters.include_snoozed = true;
+    }
+    if (params.archived) {
+      filters.archived = true;
+    }
+    if (params.starred !== undefined) {
+      filters.starred = params.starred;
+    }
 
     return filters;
   }
@@ -161,11 +323,18 @@ export class TodoSearchService {
 
     // 適用されているフィルターを収集
     if (params.q) appliedFilters.push("検索キーワード");
+    if (params.fileName) appliedFilters.push("ファイル名");
+    if (params.hasFiles !== undefined) appliedFilters.push("添付ファイルの有無");
+    if (params.commentedBy !== undefined) appliedFilters.push("コメント投稿者");
     if (params.status && params.status.length > 0) appliedFilters.push("ステータス");
     if (params.priority && params.priority.length > 0) appliedFilters.push("優先度");
     if (params.categoryId !== undefined) appliedFilters.push("カテゴリ");
     if (params.tagIds && params.tagIds.length > 0) appliedFilters.push("タグ");
+    if (params.starred !== undefined) appliedFilters.push("スター");
     if (params.dueDateFrom || params.dueDateTo) appliedFilters.push("期限日");
+    if (params.completedFrom || params.completedTo) appliedFilters.push("完了日");
+    if (params.createdFrom || params.createdTo) appliedFilters.push("作成日");
+    if (params.updatedFrom || params.updatedTo) appliedFilters.push("更新日");
 
     // フィルターが多い場合
     if (appliedFilters.length >= 2) {
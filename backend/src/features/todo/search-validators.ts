@@ -4,6 +4,8 @@
  */
 
 import { z } from "zod";
+import { getConfig } from "../../lib/config";
+import { booleanQuerySchema } from "../../shared/validators/common";
 
 /** 優先度スキーマ */
 const prioritySchema = z.enum(["low", "medium", "high"]);
@@ -25,6 +27,8 @@ const sortBySchema = z.enum([
   "title",
   "priority",
   "status",
+  "completed_at",
+  "relevance",
 ]);
 
 /** ソート順スキーマ */
@@ -41,6 +45,15 @@ export const searchTodoSchema = z.object({
   // テキスト検索
   q: z.string().optional(),
 
+  // 添付ファイル名検索
+  file_name: z.string().optional(),
+
+  // 添付ファイルの有無フィルター
+  has_files: booleanQuerySchema.optional(),
+
+  // コメント投稿者フィルター（ユーザーID）
+  commented_by: z.coerce.number().int().positive().optional(),
+
   // カテゴリフィルター（-1でカテゴリなし）
   category_id: z.coerce.number().int().optional(),
 
@@ -49,8 +62,15 @@ export const searchTodoSchema = z.object({
   // ステータスフィルター（配列形式）
   "status[]": z.union([statusSchema, z.array(statusSchema)]).optional(),
 
-  // 優先度フィルター（単一）
-  priority: prioritySchema.optional(),
+  // 優先度フィルター（単一・カンマ区切り・繰り返し指定）
+  priority: z
+    .preprocess((val) => {
+      if (val === undefined || val === null || val === "") return undefined;
+      if (Array.isArray(val)) return val;
+      if (typeof val === "string") return val.split(",").map((p) => p.trim());
+      return val;
+    }, z.array(prioritySchema).optional())
+    .optional(),
   // 優先度フィルター（配列形式）
   "priority[]": z.union([prioritySchema, z.array(prioritySchema)]).optional(),
 
@@ -72,13 +92,64 @@ export const searchTodoSchema = z.object({
   due_date_from: dateSchema.optional(),
   due_date_to: dateSchema.optional(),
 
+  // 完了日範囲フィルター
+  completed_from: dateSchema.optional(),
+  completed_to: dateSchema.optional(),
+
+  // 作成日・更新日範囲フィルター
+  created_from: dateSchema.optional(),
+  created_to: dateSchema.optional(),
+  updated_from: dateSchema.optional(),
+  updated_to: dateSchema.optional(),
+
+  // スヌーズ中のTodoを含めるか
+  include_snoozed: booleanQuerySchema.optional(),
+
+  // アーカイブ済みのTodoを対象にするか
+  archived: booleanQuerySchema.optional(),
+
+  // スターフィルター
+  starred: booleanQuerySchema.optional(),
+
   // ソート
   sort_by: sortBySchema.optional(),
   sort_order: sortOrderSchema.optional(),
 
   // ページネーション
   page: z.coerce.number().int().positive().optional(),
-  per_page: z.coerce.number().int().positive().max(100).optional(),
+  // 上限は MAX_PAGE_SIZE（設定値）
+  per_page: z.coerce
+    .number()
+    .int()
+    .positive()
+    .superRefine((val, ctx) => {
+      const max = getConfig().MAX_PAGE_SIZE;
+      if (val > max) {
+        ctx.addIssue({
+          code: z.ZodIssueCode.custom,
+          message: `per_pageは${max}以下で指定してください`,
+        });
+      }
+    })
+    .optional(),
+});
+
+/**
+ * タグ別Todo一覧クエリスキーマ
+ * タグはパスパラメータで指定するため、タグフィルターは受け付けない
+ */
+export const tagTodoSearchSchema = searchTodoSchema.omit({
+  tag_ids: true,
+  "tag_ids[]": true,
+  tag_mode: true,
+});
+
+/**
+ * カテゴリ別Todo一覧クエリスキーマ
+ * カテゴリはパスパラメータで指定するため、カテゴリフィルターは受け付けない
+ */
+export const categoryTodoSearchSchema = searchTodoSchema.omit({
+  category_id: true,
 });
 
 /** 検索入力の生の型 */
@@ -90,6 +161,12 @@ export type SearchTodoInput = z.infer<typeof searchTodoSchema>;
 export interface NormalizedSearchParams {
   /** 検索クエリ */
   q?: string;
+  /** 添付ファイル名 */
+  fileName?: string;
+  /** 添付ファイルの有無フィルター */
+  hasFiles?: boolean;
+  /** コメント投稿者のユーザーID */
+  commentedBy?: number;
   /** カテゴリID（-1でカテゴリなし） */
   categoryId?: number;
   /** ステータスフィルター */
@@ -104,8 +181,35 @@ export interface NormalizedSearchParams {
   dueDateFrom?: string;
   /** 期限終了日 */
   dueDateTo?: string;
+  /** 完了開始日 */
+  completedFrom?: string;
+  /** 完了終了日 */
+  completedTo?: string;
+  /** 作成開始日 */
+  createdFrom?: string;
+  /** 作成終了日 */
+  createdTo?: string;
+  /** 更新開始日 */
+  updatedFrom?: string;
+  /** 更新終了日 */
+  updatedTo?: string;
+  /** スヌーズ中のTodoを含めるか */
+  includeSnoozed: boolean;
+  /** アーカイブ済みのTodoを対象にするか */
+  archived: boolean;
+  /** スターフィルター */
+  starred?: boolean;
   /** ソートフィールド */
-  sortBy: "position" | "created_at" | "updated_at" | "due_date" | "title" | "priority" | "status";
+  sortBy:
+    | "position"
+    | "created_at"
+    | "updated_at"
+    | "due_date"
+    | "title"
+    | "priority"
+    | "status"
+    | "completed_at"
+    | "relevance";
   /** ソート順 */
   sortOrder: "asc" | "desc";
   /** ページ番号 */
@@ -147,8 +251,16 @@ export function normalizeSearchParams(input: SearchTodoInput): NormalizedSearchP
     tagIds = input.tag_ids;
   }
 
+  const q = input.q?.trim() || undefined;
+
+  // relevanceはキーワード指定時のみ有効。未指定時はエラーにせず作成日時の降順にフォールバック
+  const fallbackToCreatedAt = input.sort_by === "relevance" && !q;
+
   return {
-    q: input.q?.trim() || undefined,
+    q,
+    fileName: input.file_name?.trim() || undefined,
+    hasFiles: input.has_files,
+    commentedBy: input.commented_by,
     categoryId: input.category_id,
     status: normalizeArrayParam(input.status, input["status[]"]),
     priority: normalizeArrayParam(input.priority, input["priority[]"]),
@@ -156,9 +268,18 @@ export function normalizeSearchParams(input: SearchTodoInput): NormalizedSearchP
     tagMode: input.tag_mode ?? "any",
     dueDateFrom: input.due_date_from,
     dueDateTo: input.due_date_to,
-    sortBy: input.sort_by ?? "position",
-    sortOrder: input.sort_order ?? "asc",
+    completedFrom: input.completed_from,
+    completedTo: input.completed_to,
+    createdFrom: input.created_from,
+    createdTo: input.created_to,
+    updatedFrom: input.updated_from,
+    updatedTo: input.updated_to,
+    includeSnoozed: input.include_snoozed ?? false,
+    archived: input.archived ?? false,
+    starred: input.starred,
+    sortBy: fallbackToCreatedAt ? "created_at" : (input.sort_by ?? "position"),
+    sortOrder: fallbackToCreatedAt ? "desc" : (input.sort_order ?? "asc"),
     page: input.page ?? 1,
-    perPage: input.per_page ?? 20,
+    perPage: input.per_page ?? getConfig().DEFAULT_PAGE_SIZE,
   };
 }
@@ -3,20 +3,57 @@
  * @module features/todo/service
  */
 
+import { createHash } from "node:crypto";
 import { RESOURCE_NAMES, TODO } from "../../lib/constants";
 import type { RepositoryFactories } from "../../lib/container";
-import type { Database } from "../../lib/db";
-import { notFound } from "../../lib/errors";
+import { addDays, serverTimeZone, startOfWeek, todayIn } from "../../lib/date";
+import type { Database, Transaction } from "../../lib/db";
+import { conflict, notFound, validationError } from "../../lib/errors";
 import { TODO_ERROR_MESSAGES } from "../../shared/errors/messages";
 import {
+  notFoundOrForbidden,
   validateMultipleOwnership,
   validateSingleOwnership,
 } from "../../shared/validators/ownership";
 import type { TodoCategoryRepositoryInterface } from "./todo-category-repository";
 import type { TodoRepositoryInterface } from "./todo-repository";
 import type { TodoTagValidatorRepositoryInterface } from "./todo-tag-validator-repository";
-import { formatTodoResponse, type TodoResponse, type TodoUpdateData } from "./types";
-import type { CreateTodoInput, UpdateOrderInput, UpdateTodoInput } from "./validators";
+import {
+  type AgendaOptions,
+  type AgendaResponse,
+  type ArchiveCompletedTodosResponse,
+  type BulkTagsResponse,
+  type CreateTodoResult,
+  type DeleteCompletedTodosResponse,
+  formatTodoResponse,
+  isArchived,
+  type PriorityColors,
+  type TodoListOptions,
+  type TodoResponse,
+  type TodoTrendsResponse,
+  type TodoUpdateData,
+  type TodoWithNeighborsResponse,
+  type TrendBucket,
+  type TrendOptions,
+} from "./types";
+import type {
+  BulkTagsInput,
+  CreateTodoInput,
+  MoveTodoInput,
+  SnoozeTodoInput,
+  UpdateOrderInput,
+  UpdateTodoInput,
+} from "./validators";
+
+/**
+ * 作成入力のハッシュを求める（冪等キーの再利用時に同じ内容のリクエストか判定する）
+ * バリデーション後の入力を使うため、キーの順序や省略された既定値の違いは同じ内容とみなす
+ * @param input - API入力データ
+ * @returns SHA-256ハッシュ（16進数）
+ */
+function hashCreateInput(input: CreateTodoInput): string {
+  return createHash("sha256").update(JSON.stringify(input)).digest("hex");
+}
 
 /**
  * API入力をDB形式に変換するヘルパー（作成用）
@@ -24,6 +61,7 @@ import type { CreateTodoInput, UpdateOrderInput, UpdateTodoInput } from "./valid
  * completed は status から導出される:
  * - status: "completed" → completed: true
  * - status: "pending" | "in_progress" → completed: false
+ * 完了状態で作成した場合は completedAt に現在日時を設定する
  *
  * @param input - API入力データ
  * @param userId - ユーザーID
@@ -41,9 +79,11 @@ function convertCreateInputToDbFormat(
   priority: number;
   status: number;
   dueDate: string | null;
+  reminderOffsetMinutes: number | null;
   categoryId: number | null;
   position: number;
   completed: boolean;
+  completedAt: Date | null;
 } {
   return {
     userId,
@@ -52,9 +92,11 @@ function convertCreateInputToDbFormat(
     priority: TODO.PRIORITY_MAP[input.priority],
     status: TODO.STATUS_MAP[input.status],
     dueDate: input.due_date ?? null,
+    reminderOffsetMinutes: input.reminder_offset_minutes ?? null,
     categoryId: input.category_id ?? null,
     position,
     completed: input.status === "completed",
+    completedAt: input.status === "completed" ? new Date() : null,
   };
 }
 
@@ -98,13 +140,44 @@ function convertUpdateInputToDbFormat(input: UpdateTodoInput): TodoUpdateData {
   if (input.due_date !== undefined) {
     updateData.dueDate = input.due_date;
   }
+  if (input.reminder_offset_minutes !== undefined) {
+    updateData.reminderOffsetMinutes = input.reminder_offset_minutes;
+  }
+  // 通知時刻が変わりうるため、新しい通知時刻で再度通知されるようにする
+  if (input.due_date !== undefined || input.reminder_offset_minutes !== undefined) {
+    updateData.reminderSentAt = null;
+    updateData.reminderAttempts = 0;
+    updateData.reminderRetryAt = null;
+  }
   if (input.category_id !== undefined) {
     updateData.categoryId = input.category_id;
   }
+  if (input.pinned !== undefined) {
+    updateData.pinned = input.pinned;
+  }
 
   return updateData;
 }
 
+/**
+ * 期間内の集計単位の開始日を列挙する
+ * 週単位の場合は from を含む週の開始日（月曜日）から列挙する
+ * @param from - 期間の開始日（YYYY-MM-DD）
+ * @param to - 期間の終了日（YYYY-MM-DD）
+ * @param bucket - 集計単位
+ * @returns 集計単位の開始日の配列（日付順）
+ */
+function bucketDates(from: string, to: string, bucket: TrendBucket): string[] {
+  const step = bucket === "week" ? 7 : 1;
+  const dates: string[] = [];
+  let date = bucket === "week" ? startOfWeek(from) : from;
+  while (date <= to) {
+    dates.push(date);
+    date = addDays(date, step);
+  }
+  return dates;
+}
+
 /**
  * Todoサービスクラス
  * Todo関連のビジネスロジックを提供する
@@ -117,6 +190,7 @@ export class TodoService {
    * @param todoCategoryRepository - カテゴリリポジトリ（所有者検証・カウント更新用）
    * @param todoTagValidatorRepository - タグ検証リポジトリ（所有者検証用）
    * @param factories - トランザクション用リポジトリファクトリ
+   * @param priorityColors - カテゴリ未設定のTodoに使う優先度ごとの既定の色
    */
   constructor(
     private db: Database,
@@ -124,16 +198,106 @@ export class TodoService {
     private todoCategoryRepository: TodoCategoryRepositoryInterface,
     private todoTagValidatorRepository: TodoTagValidatorRepositoryInterface,
     private factories: RepositoryFactories,
+    private priorityColors: PriorityColors,
   ) {}
 
   /**
    * ユーザーのTodo一覧を取得する
    * @param userId - ユーザーID
+   * @param options - 一覧取得オプション
+   * @returns Todoレスポンスの配列
+   */
+  async list(userId: number, options: TodoListOptions = {}): Promise<TodoResponse[]> {
+    const todos = await this.todoRepository.findAll(userId, options);
+    return todos.map((data) => formatTodoResponse(data, this.priorityColors));
+  }
+
+  /**
+   * ユーザーのTodo一覧の件数を取得する
+   * @param userId - ユーザーID
+   * @param options - 一覧取得オプション
+   * @returns 件数
+   */
+  async count(userId: number, options: TodoListOptions = {}): Promise<number> {
+    return await this.todoRepository.countAll(userId, options);
+  }
+
+  /**
+   * 今日のアジェンダを取得する
+   * 期限切れ（overdue）と今日が期限（today）のTodoに分類して返す
+   * includeSnoozed の場合は今日スヌーズが明けるTodoを snoozed として返す
+   * @param userId - ユーザーID
+   * @param timeZone - 「今日」を判定するタイムゾーン（省略時はサーバーのタイムゾーン）
+   * @param options - アジェンダ取得オプション
+   * @returns アジェンダレスポンス
+   */
+  async agenda(
+    userId: number,
+    timeZone: string = serverTimeZone(),
+    options: AgendaOptions = {},
+  ): Promise<AgendaResponse> {
+    const today = todayIn(timeZone);
+    const todos = await this.todoRepository.findAgenda(userId, today, options);
+
+    const result: AgendaResponse = { date: today, timezone: timeZone, overdue: [], today: [] };
+    const snoozed: TodoResponse[] = [];
+
+    for (const data of todos) {
+      const { dueDate } = data.todo;
+      if (dueDate !== null && dueDate < today) {
+        result.overdue.push(formatTodoResponse(data, this.priorityColors));
+      } else if (dueDate === today) {
+        result.today.push(formatTodoResponse(data, this.priorityColors));
+      } else {
+        snoozed.push(formatTodoResponse(data, this.priorityColors));
+      }
+    }
+
+    if (options.includeSnoozed) {
+      result.snoozed = snoozed;
+    }
+    return result;
+  }
+
+  /**
+   * リマインダーの通知時刻が現在から指定期間内に入るTodoを取得する（通知時刻順）
+   * 通知時刻は期限日の0時（指定タイムゾーン）から reminder_offset_minutes 分前とする
+   * @param userId - ユーザーID
+   * @param windowMinutes - 現在時刻から何分先までを対象とするか
+   * @param timeZone - 期限日の0時を判定するタイムゾーン（省略時はサーバーのタイムゾーン）
    * @returns Todoレスポンスの配列
    */
-  async list(userId: number): Promise<TodoResponse[]> {
-    const todos = await this.todoRepository.findAll(userId);
-    return todos.map(formatTodoResponse);
+  async reminders(
+    userId: number,
+    windowMinutes: number,
+    timeZone: string = serverTimeZone(),
+  ): Promise<TodoResponse[]> {
+    const from = new Date();
+    const to = new Date(from.getTime() + windowMinutes * 60 * 1000);
+    const todos = await this.todoRepository.findReminders(userId, { from, to, timeZone });
+    return todos.map((data) => formatTodoResponse(data, this.priorityColors));
+  }
+
+  /**
+   * 作成数・完了数の推移を取得する
+   * 件数0の集計単位も含め、期間内の全ての集計単位を日付順に返す
+   * 週単位の場合は、先頭の週も月曜日から集計する
+   * @param userId - ユーザーID
+   * @param options - 推移取得オプション
+   * @returns 集計単位ごとの作成数・完了数の配列
+   */
+  async trends(userId: number, options: TrendOptions): Promise<TodoTrendsResponse> {
+    const counts = await this.todoRepository.countTrends(userId, {
+      ...options,
+      from: options.bucket === "week" ? startOfWeek(options.from) : options.from,
+      timeZone: options.timeZone ?? serverTimeZone(),
+    });
+
+    return bucketDates(options.from, options.to, options.bucket).map((date) => ({
+      date,
+      created: counts.created.get(date) ?? 0,
+      completed: counts.completed.get(date) ?? 0,
+    }));
   }
 
   /**
@@ -146,9 +310,47 @@ export class TodoService {
   async show(id: number, userId: number): Promise<TodoResponse> {
     const todo = await this.todoRepository.findById(id, userId);
     if (!todo) {
-      throw notFound(RESOURCE_NAMES.TODO, id);
+      throw await notFoundOrForbidden(RESOURCE_NAMES.TODO, id, this.todoRepository);
+    }
+    return formatTodoResponse(todo, this.priorityColors);
+  }
+
+  /**
+   * Todoの詳細を、一覧（position順）で前後に並ぶTodoのIDとあわせて取得する
+   * @param id - TodoのID
+   * @param userId - ユーザーID
+   * @returns 前後のTodoのID付きTodoレスポンス
+   * @throws NotFoundError - Todoが見つからない場合
+   */
+  async showWithNeighbors(id: number, userId: number): Promise<TodoWithNeighborsResponse> {
+    const todo = await this.todoRepository.findById(id, userId);
+    if (!todo) {
+      throw await notFoundOrForbidden(RESOURCE_NAMES.TODO, id, this.todoRepository);
     }
-    return formatTodoResponse(todo);
+    const { prevId, nextId } = await this.todoRepository.findNeighborIds(todo.todo);
+    return {
+      ...formatTodoResponse(todo, this.priorityColors),
+      prev_id: prevId,
+      next_id: nextId,
+    };
+  }
+
+  /**
+   * 指定したIDのTodoを一括取得する
+   * 存在しないIDや他ユーザーのTodoのIDは結果から除外する
+   * @param ids - TodoのIDの配列
+   * @param userId - ユーザーID
+   * @returns 指定したIDの順に並べたTodoレスポンスの配列（重複したIDは最初の1件のみ）
+   */
+  async batch(ids: number[], userId: number): Promise<TodoResponse[]> {
+    const uniqueIds = [...new Set(ids)];
+    const todos = await this.todoRepository.findByIdsWithRelations(uniqueIds, userId);
+    const todoMap = new Map(todos.map((data) => [data.todo.id, data]));
+
+    return uniqueIds.flatMap((id) => {
+      const data = todoMap.get(id);
+      return data ? [formatTodoResponse(data, this.priorityColors)] : [];
+    });
   }
 
   /**
@@ -156,51 +358,144 @@ export class TodoService {
    * @param input - 作成データ
    * @param userId - ユーザーID
    * @returns 作成されたTodoレスポンス
-   * @throws ForbiddenError - 他ユーザーのCategory/Tagを使用した場合
+   * @throws ForbiddenError - 他ユーザーのCategoryを使用した場合
+   * @throws ValidationError - 使用できないTag/Labelを指定した場合
    */
   async create(input: CreateTodoInput, userId: number): Promise<TodoResponse> {
-    // カテゴリの所有者検証（トランザクション外で事前検証）
+    await this.validateCreateReferences(input, userId);
+
+    // トランザクション内で作成処理を実行
+    return await this.db.transaction((tx) => this.createInTransaction(tx, input, userId));
+  }
+
+  /**
+   * 冪等キー付きでTodoを作成する
+   * 有効期限内の同じキーで作成済みの場合は新たに作成せず、そのTodoを返す
+   * @param input - 作成データ
+   * @param userId - ユーザーID
+   * @param idempotencyKey - 冪等キー（Idempotency-Key ヘッダーの値）
+   * @returns 作成されたTodoレスポンスと再送かどうか
+   * @throws ForbiddenError - 他ユーザーのCategoryを使用した場合
+   * @throws ValidationError - 使用できないTag/Labelを指定した場合
+   * @throws ConflictError - 同じキーのリクエストが並行して処理された場合、
+   *   または同じキーが異なる内容のリクエストで使用された場合
+   */
+  async createIdempotent(
+    input: CreateTodoInput,
+    userId: number,
+    idempotencyKey: string,
+  ): Promise<CreateTodoResult> {
+    const requestHash = hashCreateInput(input);
+    const keyRepo = this.factories.createIdempotencyKeyRepository(this.db);
+    const existing = await keyRepo.findActive(userId, idempotencyKey);
+    if (existing) {
+      // 別の内容で再利用されたキーに最初のTodoを返すと、クライアントが取り違えに気付けない
+      // （ハッシュ導入前に登録されたキーは照合できないため、そのまま再送として扱う）
+      if (existing.requestHash !== null && existing.requestHash !== requestHash) {
+        throw conflict(TODO_ERROR_MESSAGES.IDEMPOTENCY_KEY_REUSED, "idempotency-key");
+      }
+      return { todo: await this.show(existing.todoId, userId), replayed: true };
+    }
+
+    await this.validateCreateReferences(input, userId);
+
+    const expiresAt = new Date(Date.now() + TODO.IDEMPOTENCY_KEY_TTL_SECONDS * 1000);
+    const todo = await this.db.transaction(async (tx) => {
+      const created = await this.createInTransaction(tx, input, userId);
+
+      // 同じキーが並行して登録された場合はロールバックして409を返す
+      const txKeyRepo = this.factories.createIdempotencyKeyRepository(tx);
+      const registered = await txKeyRepo.create(
+        userId,
+        idempotencyKey,
+        created.id,
+        requestHash,
+        expiresAt,
+      );
+      if (!registered) {
+        throw conflict(TODO_ERROR_MESSAGES.IDEMPOTENCY_KEY_IN_USE);
+      }
+
+      return created;
+    });
+
+    return { todo, replayed: false };
+  }
+
+  /**
+   * 作成時に参照するカテゴリ・タグ・ラベルの所有者を検証する
+   * トランザクション外で事前に検証する
+   * @param input - 作成データ
+   * @param userId - ユーザーID
+   * @throws ForbiddenError - 他ユーザーのCategoryを使用した場合
+   * @throws ValidationError - 使用できないTag/Labelを指定した場合
+   */
+  private async validateCreateReferences(input: CreateTodoInput, userId: number): Promise<void> {
     if (input.category_id) {
       await this.validateCategoryOwnership(input.category_id, userId);
     }
-
-    // タグの所有者検証（トランザクション外で事前検証）
     if (input.tag_ids && input.tag_ids.length > 0) {
       await this.validateTagsOwnership(input.tag_ids, userId);
     }
+    if (input.label_ids && input.label_ids.length > 0) {
+      await this.validateLabelsOwnership(input.label_ids, userId);
+    }
+  }
 
-    // トランザクション内で作成処理を実行
-    return await this.db.transaction(async (tx) => {
-      const txTodoRepo = this.factories.createTodoRepository(tx);
-      const txTodoTagRepo = this.factories.createTodoTagRepository(tx);
-      const txCategoryRepo = this.factories.createCategoryRepository(tx);
-
-      // 最大positionを取得
+  /**
+   * トランザクション内でTodoを作成する
+   * 所有者検証は呼び出し側で事前に行うこと
+   * @param tx - トランザクション
+   * @param input - 作成データ
+   * @param userId - ユーザーID
+   * @returns 作成されたTodoレスポンス
+   */
+  private async createInTransaction(
+    tx: Transaction,
+    input: CreateTodoInput,
+    userId: number,
+  ): Promise<TodoResponse> {
+    const txTodoRepo = this.factories.createTodoRepository(tx);
+    const txTodoTagRepo = this.factories.createTodoTagRepository(tx);
+    const txCategoryRepo = this.factories.createCategoryRepository(tx);
+
+    let newPosition: number;
+    if (input.position !== undefined) {
+      // 指定位置以降のTodoを後ろにずらしてから、指定位置に挿入する
+      await txTodoRepo.shiftPositions(userId, input.position, TODO.POSITION_GAP);
+      newPosition = input.position;
+    } else {
+      // 末尾に POSITION_GAP 間隔で追加する
       const maxPosition = await txTodoRepo.getMaxPosition(userId);
-      const newPosition = maxPosition + 1;
+      newPosition = Math.max(maxPosition, 0) + TODO.POSITION_GAP;
+    }
 
-      // 入力をDB形式に変換してTodoを作成
-      const todoData = convertCreateInputToDbFormat(input, userId, newPosition);
-      const todo = await txTodoRepo.create(todoData);
+    // 入力をDB形式に変換してTodoを作成
+    const todoData = convertCreateInputToDbFormat(input, userId, newPosition);
+    const todo = await txTodoRepo.create(todoData);
 
-      // タグを関連付け
-      if (input.tag_ids && input.tag_ids.length > 0) {
-        await txTodoTagRepo.syncTags(todo.id, input.tag_ids);
-      }
+    // タグを関連付け
+    if (input.tag_ids && input.tag_ids.length > 0) {
+      await txTodoTagRepo.syncTags(todo.id, input.tag_ids);
+    }
 
-      // カテゴリのカウントを増加
-      if (input.category_id) {
-        await txCategoryRepo.incrementTodosCount(input.category_id);
-      }
+    // ラベルを関連付け
+    if (input.label_ids && input.label_ids.length > 0) {
+      await this.factories.createTodoLabelRepository(tx).syncLabels(todo.id, input.label_ids);
+    }
 
-      // リレーション付きで再取得
-      const created = await txTodoRepo.findById(todo.id, userId);
-      if (!created) {
-        throw notFound(RESOURCE_NAMES.TODO, todo.id);
-      }
+    // カテゴリのカウントを増加
+    if (input.category_id) {
+      await txCategoryRepo.incrementTodosCount(input.category_id);
+    }
 
-      return formatTodoResponse(created);
-    });
+    // リレーション付きで再取得
+    const created = await txTodoRepo.findById(todo.id, userId);
+    if (!created) {
+      throw notFound(RESOURCE_NAMES.TODO, todo.id);
+    }
+
+    return formatTodoResponse(created, this.priorityColors);
   }
 
   /**
@@ -210,13 +505,14 @@ export class TodoService {
    * @param userId - ユーザーID
    * @returns 更新されたTodoレスポンス
    * @throws NotFoundError - Todoが見つからない場合
-   * @throws ForbiddenError - 他ユーザーのCategory/Tagを使用した場合
+   * @throws ForbiddenError - 他ユーザーのCategoryを使用した場合
+   * @throws ValidationError - 使用できないTag/Labelを指定した場合
    */
   async update(id: number, input: UpdateTodoInput, userId: number): Promise<TodoResponse> {
     // 既存のTodoを取得（トランザクション外で事前検証）
     const existing = await this.todoRepository.findById(id, userId);
     if (!existing) {
-      throw notFound(RESOURCE_NAMES.TODO, id);
+      throw await notFoundOrForbidden(RESOURCE_NAMES.TODO, id, this.todoRepository);
     }
 
     const oldCategoryId = existing.todo.categoryId;
@@ -231,6 +527,11 @@ export class TodoService {
       await this.validateTagsOwnership(input.tag_ids, userId);
     }
 
+    // ラベルの所有者検証（トランザクション外で事前検証）
+    if (input.label_ids !== undefined && input.label_ids.length > 0) {
+      await this.validateLabelsOwnership(input.label_ids, userId);
+    }
+
     // トランザクション内で更新処理を実行
     return await this.db.transaction(async (tx) => {
       const txTodoRepo = this.factories.createTodoRepository(tx);
@@ -240,6 +541,22 @@ export class TodoService {
       // 入力をDB形式に変換
       const updateData = convertUpdateInputToDbFormat(input);
 
+      // 完了日時の同期（未完了→完了で現在日時を設定、完了→未完了でクリア）
+      // 既に完了済みの場合は完了日時を維持する
+      if (updateData.completed === true && !existing.todo.completed) {
+        updateData.completedAt = new Date();
+      } else if (updateData.completed === false) {
+        updateData.completedAt = null;
+      }
+
+      // アーカイブ状態の変更（status/completedは変更しない）
+      // 既にアーカイブ済みの場合はアーカイブ日時を維持する
+      if (input.archived === true && !isArchived(existing.todo)) {
+        updateData.archivedAt = new Date();
+      } else if (input.archived === false) {
+        updateData.archivedAt = null;
+      }
+
       // Todoを更新
       if (Object.keys(updateData).length > 0) {
         await txTodoRepo.update(id, userId, updateData);
@@ -250,6 +567,11 @@ export class TodoService {
         await txTodoTagRepo.syncTags(id, input.tag_ids);
       }
 
+      // ラベルを同期
+      if (input.label_ids !== undefined) {
+        await this.factories.createTodoLabelRepository(tx).syncLabels(id, input.label_ids);
+      }
+
       // カテゴリのカウントを更新
       const newCategoryId = input.category_id !== undefined ? input.category_id : oldCategoryId;
       if (oldCategoryId !== newCategoryId) {
@@ -267,7 +589,7 @@ export class TodoService {
         throw notFound(RESOURCE_NAMES.TODO, id);
       }
 
-      return formatTodoResponse(updated);
+      return formatTodoResponse(updated, this.priorityColors);
     });
   }
 
@@ -281,7 +603,7 @@ export class TodoService {
     // 既存のTodoを取得（トランザクション外で事前検証）
     const existing = await this.todoRepository.findById(id, userId);
     if (!existing) {
-      throw notFound(RESOURCE_NAMES.TODO, id);
+      throw await notFoundOrForbidden(RESOURCE_NAMES.TODO, id, this.todoRepository);
     }
 
     const categoryId = existing.todo.categoryId;
@@ -301,6 +623,71 @@ export class TodoService {
     });
   }
 
+  /**
+   * 完了済みのTodoをすべて削除する
+   * 削除対象がない場合も成功として扱う
+   * @param userId - ユーザーID
+   * @returns 削除した件数
+   */
+  async destroyCompleted(userId: number): Promise<DeleteCompletedTodosResponse> {
+    return await this.db.transaction(async (tx) => {
+      const txTodoRepo = this.factories.createTodoRepository(tx);
+      const txCategoryRepo = this.factories.createCategoryRepository(tx);
+
+      // Todoを削除（todo_tagsはカスケード削除される）
+      const deleted = await txTodoRepo.deleteCompleted(userId);
+
+      // カテゴリごとに削除した件数だけカウントを減少
+      const countsByCategory = new Map<number, number>();
+      for (const todo of deleted) {
+        if (todo.categoryId) {
+          countsByCategory.set(todo.categoryId, (countsByCategory.get(todo.categoryId) ?? 0) + 1);
+        }
+      }
+      for (const [categoryId, count] of countsByCategory) {
+        await txCategoryRepo.decrementTodosCount(categoryId, count);
+      }
+
+      return { deleted_count: deleted.length };
+    });
+  }
+
+  /**
+   * 完了済みで未アーカイブのTodoをすべてアーカイブする
+   * 対象がない場合も成功として扱う
+   * @param userId - ユーザーID
+   * @returns アーカイブした件数
+   */
+  async archiveCompleted(userId: number): Promise<ArchiveCompletedTodosResponse> {
+    const archivedCount = await this.todoRepository.archiveCompleted(userId);
+    return { archived_count: archivedCount };
+  }
+
+  /**
+   * Todoをスヌーズする
+   * 期限日（due_date）は変更せず、指定日まで一覧・検索から非表示にする
+   * @param id - TodoのID
+   * @param input - スヌーズ入力（until: null で解除）
+   * @param userId - ユーザーID
+   * @returns 更新されたTodoレスポンス
+   * @throws NotFoundError - Todoが見つからない場合
+   */
+  async snooze(id: number, input: SnoozeTodoInput, userId: number): Promise<TodoResponse> {
+    return await this.updateAttributes(id, userId, { snoozedUntil: input.until });
+  }
+
+  /**
+   * Todoのスターを設定・解除する
+   * @param id - TodoのID
+   * @param starred - スターを付ける場合true、外す場合false
+   * @param userId - ユーザーID
+   * @returns 更新されたTodoレスポンス
+   * @throws NotFoundError - Todoが見つからない場合
+   */
+  async setStarred(id: number, starred: boolean, userId: number): Promise<TodoResponse> {
+    return await this.updateAttributes(id, userId, { starred });
+  }
+
   /**
    * Todoの順序を一括更新する
    * @param input - 順序更新データ
@@ -322,6 +709,111 @@ export class TodoService {
     await this.todoRepository.updatePositions(input.todos, userId);
   }
 
+  /**
+   * Todoを別のTodoの前後に移動する
+   * 基準のTodoとその隣のTodoの間に空きがあれば移動するTodoのみを更新し、
+   * 空きがない場合はTodo全体のpositionを振り直す
+   * @param id - 移動するTodoのID
+   * @param input - 移動先（after_id または before_id）
+   * @param userId - ユーザーID
+   * @returns 移動したTodoレスポンス
+   * @throws NotFoundError - Todoまたは基準となるTodoが見つからない場合
+   * @throws ValidationError - 自身を基準に指定した場合
+   */
+  async move(id: number, input: MoveTodoInput, userId: number): Promise<TodoResponse> {
+    const field = input.after_id !== undefined ? "after_id" : "before_id";
+    const anchorId = input.after_id ?? input.before_id;
+    if (anchorId === undefined) {
+      throw validationError(TODO_ERROR_MESSAGES.MOVE_TARGET_REQUIRED, {
+        after_id: [TODO_ERROR_MESSAGES.MOVE_TARGET_REQUIRED],
+      });
+    }
+    if (anchorId === id) {
+      throw validationError(TODO_ERROR_MESSAGES.MOVE_SELF, {
+        [field]: [TODO_ERROR_MESSAGES.MOVE_SELF],
+      });
+    }
+
+    const existing = await this.todoRepository.findById(id, userId);
+    if (!existing) {
+      throw await notFoundOrForbidden(RESOURCE_NAMES.TODO, id, this.todoRepository);
+    }
+    const anchor = await this.todoRepository.findById(anchorId, userId);
+    if (!anchor) {
+      throw await notFoundOrForbidden(RESOURCE_NAMES.TODO, anchorId, this.todoRepository);
+    }
+
+    const placement = input.after_id !== undefined ? "after" : "before";
+    await this.db.transaction(async (tx) => {
+      await this.factories.createTodoRepository(tx).insertBetween(userId, id, anchorId, placement);
+    });
+
+    const result = await this.todoRepository.findById(id, userId);
+    if (!result) {
+      throw notFound(RESOURCE_NAMES.TODO, id);
+    }
+    return formatTodoResponse(result, this.priorityColors);
+  }
+
+  /**
+   * 複数のTodoにタグを一括で付け外しする
+   * 既に付いているタグの追加・付いていないタグの削除はスキップする
+   * @param input - 一括付け外しデータ
+   * @param userId - ユーザーID
+   * @returns 対象TodoのIDと追加・削除された紐付けの数
+   * @throws ForbiddenError - 他ユーザーのTodoが含まれている場合
+   * @throws ValidationError - 使用できないタグが含まれている場合
+   */
+  async bulkUpdateTags(input: BulkTagsInput, userId: number): Promise<BulkTagsResponse> {
+    // Todoとタグの所有者検証（トランザクション外で事前検証）
+    await validateMultipleOwnership(
+      input.todo_ids,
+      userId,
+      this.todoRepository,
+      TODO_ERROR_MESSAGES.BULK_FORBIDDEN,
+    );
+    await this.validateTagsOwnership(input.add_tag_ids, userId, "add_tag_ids");
+    await this.validateTagsOwnership(input.remove_tag_ids, userId, "remove_tag_ids");
+
+    // 追加と削除を同一トランザクションで実行
+    return await this.db.transaction(async (tx) => {
+      const txTodoTagRepo = this.factories.createTodoTagRepository(tx);
+      const addedCount = await txTodoTagRepo.addTags(input.todo_ids, input.add_tag_ids);
+      const removedCount = await txTodoTagRepo.removeTags(input.todo_ids, input.remove_tag_ids);
+
+      return {
+        todo_ids: input.todo_ids,
+        added_count: addedCount,
+        removed_count: removedCount,
+      };
+    });
+  }
+
+  /**
+   * カテゴリ・タグに影響しない属性を更新し、リレーション付きで再取得する
+   * @param id - TodoのID
+   * @param userId - ユーザーID
+   * @param data - 更新データ
+   * @returns 更新されたTodoレスポンス
+   * @throws NotFoundError - Todoが見つからない場合
+   */
+  private async updateAttributes(
+    id: number,
+    userId: number,
+    data: TodoUpdateData,
+  ): Promise<TodoResponse> {
+    const updated = await this.todoRepository.update(id, userId, data);
+    if (!updated) {
+      throw await notFoundOrForbidden(RESOURCE_NAMES.TODO, id, this.todoRepository);
+    }
+
+    const result = await this.todoRepository.findById(id, userId);
+    if (!result) {
+      throw notFound(RESOURCE_NAMES.TODO, id);
+    }
+    return formatTodoResponse(result, this.priorityColors);
+  }
+
   /**
    * カテゴリの所有者を検証する
    * @param categoryId - カテゴリID
@@ -339,16 +831,40 @@ export class TodoService {
 
   /**
    * タグの所有者を検証する
+   * 使用できないタグIDはエラー詳細に列挙する
    * @param tagIds - タグIDの配列
    * @param userId - ユーザーID
-   * @throws ForbiddenError - 他ユーザーのタグが含まれている場合
+   * @param field - エラー詳細のフィールド名
+   * @throws ValidationError - 他ユーザーのタグや存在しないタグが含まれている場合
    */
-  private async validateTagsOwnership(tagIds: number[], userId: number): Promise<void> {
+  private async validateTagsOwnership(
+    tagIds: number[],
+    userId: number,
+    field = "tag_ids",
+  ): Promise<void> {
     await validateMultipleOwnership(
       tagIds,
       userId,
       this.todoTagValidatorRepository,
       TODO_ERROR_MESSAGES.TAGS_FORBIDDEN,
+      field,
+    );
+  }
+
+  /**
+   * ラベルの所有者を検証する
+   * 使用できないラベルIDはエラー詳細に列挙する
+   * @param labelIds - ラベルIDの配列
+   * @param userId - ユーザーID
+   * @throws ValidationError - 他ユーザーのラベルや存在しないラベルが含まれている場合
+   */
+  private async validateLabelsOwnership(labelIds: number[], userId: number): Promise<void> {
+    await validateMultipleOwnership(
+      labelIds,
+      userId,
+      this.factories.createTodoLabelRepository(this.db),
+      TODO_ERROR_MESSAGES.LABELS_FORBIDDEN,
+      "label_ids",
     );
   }
 }
@@ -30,8 +30,9 @@ export interface TodoCategoryRepositoryInterface {
   /**
    * カテゴリのTodoカウントを減少させる
    * @param id - カテゴリID
+   * @param count - 減少させる件数（デフォルト: 1）
    */
-  decrementTodosCount(id: number): Promise<void>;
+  decrementTodosCount(id: number, count?: number): Promise<void>;
 }
 
 /**
@@ -76,12 +77,13 @@ export class TodoCategoryRepository implements TodoCategoryRepositoryInterface {
   /**
    * カテゴリのTodoカウントを減少させる
    * @param id - カテゴリID
+   * @param count - 減少させる件数（デフォルト: 1）
    */
-  async decrementTodosCount(id: number): Promise<void> {
+  async decrementTodosCount(id: number, count = 1): Promise<void> {
     await this.db
       .update(categories)
       .set({
-        todosCount: sql`GREATEST(${categories.todosCount} - 1, 0)`,
+        todosCount: sql`GREATEST(${categories.todosCount} - ${count}, 0)`,
         updatedAt: new Date(),
       })
       .where(eq(categories.id, id));
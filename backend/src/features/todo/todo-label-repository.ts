@@ -0,0 +1,69 @@
+/**
+ * TodoLabelリポジトリ
+ * ラベルの所有者検証とTodoへの紐付けを提供する
+ * @module features/todo/todo-label-repository
+ */
+
+import { and, eq, inArray } from "drizzle-orm";
+import type { DatabaseOrTransaction } from "../../lib/db";
+import { type Label, labels, todoLabels } from "../../models/schema";
+
+/**
+ * TodoLabelリポジトリのインターフェース
+ */
+export interface TodoLabelRepositoryInterface {
+  /**
+   * 複数のIDとユーザーIDでラベルを検索する
+   * @param ids - ラベルIDの配列
+   * @param userId - ユーザーID
+   * @returns ラベルの配列
+   */
+  findByIds(ids: number[], userId: number): Promise<Label[]>;
+
+  /**
+   * Todoのラベルを同期する（既存のラベルを削除して新しいラベルを挿入）
+   * @param todoId - TodoのID
+   * @param labelIds - ラベルIDの配列
+   */
+  syncLabels(todoId: number, labelIds: number[]): Promise<void>;
+}
+
+/**
+ * TodoLabelリポジトリの実装
+ */
+export class TodoLabelRepository implements TodoLabelRepositoryInterface {
+  /**
+   * TodoLabelRepositoryを作成する
+   * @param db - Drizzleデータベースまたはトランザクションインスタンス
+   */
+  constructor(private db: DatabaseOrTransaction) {}
+
+  /**
+   * 複数のIDとユーザーIDでラベルを検索する
+   * @param ids - ラベルIDの配列
+   * @param userId - ユーザーID
+   * @returns ラベルの配列
+   */
+  async findByIds(ids: number[], userId: number): Promise<Label[]> {
+    if (ids.length === 0) {
+      return [];
+    }
+    return await this.db
+      .select()
+      .from(labels)
+      .where(and(inArray(labels.id, ids), eq(labels.userId, userId)));
+  }
+
+  /**
+   * Todoのラベルを同期する（既存のラベルを削除して新しいラベルを挿入）
+   * @param todoId - TodoのID
+   * @param labelIds - ラベルIDの配列
+   */
+  async syncLabels(todoId: number, labelIds: number[]): Promise<void> {
+    await this.db.delete(todoLabels).where(eq(todoLabels.todoId, todoId));
+
+    if (labelIds.length > 0) {
+      await this.db.insert(todoLabels).values(labelIds.map((labelId) => ({ todoId, labelId })));
+    }
+  }
+}
@@ -3,30 +3,124 @@
  * @module features/todo/todo-repository
  */
 
-import { and, asc, eq, inArray, max } from "drizzle-orm";
+import {
+  and,
+  asc,
+  count,
+  desc,
+  eq,
+  gte,
+  inArray,
+  isNull,
+  lte,
+  max,
+  min,
+  ne,
+  or,
+  type SQL,
+  sql,
+} from "drizzle-orm";
+import { TODO } from "../../lib/constants";
+import { todayIn } from "../../lib/date";
 import type { DatabaseOrTransaction } from "../../lib/db";
 import {
   type Category,
   categories,
+  type Label,
+  labels,
   type NewTodo,
   type Tag,
   type Todo,
   tags,
+  todoLabels,
   todos,
   todoTags,
 } from "../../models/schema";
-import type { TodoWithRelations } from "./types";
+import {
+  archivedCondition,
+  dueAtExpression,
+  notSnoozedCondition,
+  remindAtExpression,
+} from "./conditions";
+import type {
+  AgendaOptions,
+  ReminderOptions,
+  TodoInsertPlacement,
+  TodoListOptions,
+  TodoNeighborIds,
+  TodoTrendCounts,
+  TodoWithRelations,
+  TrendOptions,
+} from "./types";
 
 /**
  * Todoリポジトリのインターフェース
  */
 export interface TodoRepositoryInterface {
   /**
-   * ユーザーのTodo一覧を取得する（position順）
+   * ユーザーのTodo一覧を取得する（ピン留めしたTodoを先頭に、position順）
    * @param userId - ユーザーID
+   * @param options - 一覧取得オプション
    * @returns TodoWithRelationsの配列
    */
-  findAll(userId: number): Promise<TodoWithRelations[]>;
+  findAll(userId: number, options?: TodoListOptions): Promise<TodoWithRelations[]>;
+
+  /**
+   * ユーザーのTodo一覧の件数を取得する（findAll と同じ条件で数える）
+   * @param userId - ユーザーID
+   * @param options - 一覧取得オプション
+   * @returns 件数
+   */
+  countAll(userId: number, options?: TodoListOptions): Promise<number>;
+
+  /**
+   * 指定日のアジェンダ対象のTodoを取得する（期限日順）
+   * 期限日が指定日以前のTodo（オプションで指定日にスヌーズが明けるTodo）を対象とする
+   * @param userId - ユーザーID
+   * @param today - 基準日（YYYY-MM-DD）
+   * @param options - アジェンダ取得オプション
+   * @returns TodoWithRelationsの配列
+   */
+  findAgenda(
+    userId: number,
+    today: string,
+    options?: AgendaOptions,
+  ): Promise<TodoWithRelations[]>;
+
+  /**
+   * リマインダーの通知時刻が指定期間内に入るTodoを取得する（通知時刻順）
+   * 完了済み・アーカイブ済みのTodoは対象外とする
+   * @param userId - ユーザーID
+   * @param options - リマインダー取得オプション
+   * @returns TodoWithRelationsの配列
+   */
+  findReminders(userId: number, options: ReminderOptions): Promise<TodoWithRelations[]>;
+
+  /**
+   * 通知時刻を過ぎ、まだ通知していないTodoを全ユーザーから取得し、通知済みにして確保する
+   * 期限日が終わったTodo・完了済み・アーカイブ済み・再送待ちのTodoは対象外とする
+   * @param now - 基準日時（通知日時として記録する）
+   * @param timeZone - 期限日の0時を判定するタイムゾーン
+   * @param limit - 最大件数
+   * @returns 確保したTodoの配列
+   */
+  claimDueReminders(now: Date, timeZone: string, limit: number): Promise<Todo[]>;
+
+  /**
+   * リマインダーの通知失敗を記録する
+   * @param id - TodoのID
+   * @param retryAt - 次回の再送日時（nullの場合は再送せず、通知済みのままにする）
+   */
+  recordReminderFailure(id: number, retryAt: Date | null): Promise<void>;
+
+  /**
+   * 作成数・完了数を集計単位（日・週）ごとに集計する
+   * 日時は指定タイムゾーンの日付に変換してから集計する
+   * @param userId - ユーザーID
+   * @param options - 推移取得オプション
+   * @returns 集計単位の開始日ごとの件数（件数0の集計単位は含まない）
+   */
+  countTrends(userId: number, options: Required<TrendOptions>): Promise<TodoTrendCounts>;
 
   /**
    * IDとユーザーIDでTodoを取得する（リレーション含む）
@@ -36,6 +130,13 @@ export interface TodoRepositoryInterface {
    */
   findById(id: number, userId: number): Promise<TodoWithRelations | undefined>;
 
+  /**
+   * IDでTodoが存在するかを確認する（所有者を問わない）
+   * @param id - TodoのID
+   * @returns 存在する場合true
+   */
+  existsById(id: number): Promise<boolean>;
+
   /**
    * 複数のIDとユーザーIDでTodoを取得する
    * @param ids - TodoのIDの配列
@@ -44,6 +145,14 @@ export interface TodoRepositoryInterface {
    */
   findByIds(ids: number[], userId: number): Promise<Todo[]>;
 
+  /**
+   * 複数のIDとユーザーIDでTodoを取得する（リレーション含む）
+   * @param ids - TodoのIDの配列
+   * @param userId - ユーザーID
+   * @returns TodoWithRelationsの配列（順序は保証しない）
+   */
+  findByIdsWithRelations(ids: number[], userId: number): Promise<TodoWithRelations[]>;
+
   /**
    * Todoを作成する
    * @param data - 作成データ
@@ -72,6 +181,20 @@ export interface TodoRepositoryInterface {
    */
   delete(id: number, userId: number): Promise<boolean>;
 
+  /**
+   * ユーザーの完了済みTodoをすべて削除する
+   * @param userId - ユーザーID
+   * @returns 削除したTodoの配列
+   */
+  deleteCompleted(userId: number): Promise<Todo[]>;
+
+  /**
+   * ユーザーの完了済みで未アーカイブのTodoをすべてアーカイブする
+   * @param userId - ユーザーID
+   * @returns アーカイブした件数
+   */
+  archiveCompleted(userId: number): Promise<number>;
+
   /**
    * ユーザーの最大positionを取得する
    * @param userId - ユーザーID
@@ -85,6 +208,68 @@ export interface TodoRepositoryInterface {
    * @param userId - ユーザーID
    */
   updatePositions(updates: Array<{ id: number; position: number }>, userId: number): Promise<void>;
+
+  /**
+   * 指定position以降のTodoのpositionをずらす
+   * @param userId - ユーザーID
+   * @param from - このposition以上のTodoを対象とする
+   * @param amount - 加算する値
+   */
+  shiftPositions(userId: number, from: number, amount: number): Promise<void>;
+
+  /**
+   * Todoを基準のTodoと、その隣のTodoの間に移動する
+   * 間にpositionの空きがあれば移動するTodoのみを更新し、空きがない場合は
+   * ユーザーのTodo全体のpositionを振り直してから移動する
+   * @param userId - ユーザーID
+   * @param id - 移動するTodoのID
+   * @param anchorId - 基準のTodoのID
+   * @param placement - 基準のTodoの直後（after）か直前（before）か
+   */
+  insertBetween(
+    userId: number,
+    id: number,
+    anchorId: number,
+    placement: TodoInsertPlacement,
+  ): Promise<void>;
+
+  /**
+   * 一覧（position順）で前後に並ぶTodoのIDを取得する
+   * 対象のTodoと同じアーカイブ状態で、スヌーズ中でないTodoの中から探す
+   * @param todo - 基準のTodo
+   * @returns 前後のTodoのID（存在しない場合はnull）
+   */
+  findNeighborIds(todo: Todo): Promise<TodoNeighborIds>;
+}
+
+/**
+ * 一覧の絞り込み条件を構築する
+ * @param userId - ユーザーID
+ * @param options - 一覧取得オプション
+ * @returns SQL条件
+ */
+function listConditions(userId: number, options: TodoListOptions): SQL | undefined {
+  const conditions: SQL[] = [
+    eq(todos.userId, userId),
+    archivedCondition(options.archived ?? false),
+  ];
+
+  // スヌーズ中のTodoはデフォルトで除外
+  if (!options.includeSnoozed) {
+    conditions.push(notSnoozedCondition(todayIn()));
+  }
+
+  return and(...conditions);
+}
+
+/**
+ * 2つのpositionの間の値を求める
+ * @param lower - 前のposition
+ * @param upper - 後のposition
+ * @returns 中間のposition（間に整数の空きがない場合はnull）
+ */
+function positionBetween(lower: number, upper: number): number | null {
+  return upper - lower > 1 ? Math.floor((lower + upper) / 2) : null;
 }
 
 /**
@@ -98,18 +283,205 @@ export class TodoRepository implements TodoRepositoryInterface {
   constructor(private db: DatabaseOrTransaction) {}
 
   /**
-   * ユーザーのTodo一覧を取得する（position順）
+   * ユーザーのTodo一覧を取得する（ピン留めしたTodoを先頭に、position順）
    * @param userId - ユーザーID
+   * @param options - 一覧取得オプション
    * @returns TodoWithRelationsの配列
    */
-  async findAll(userId: number): Promise<TodoWithRelations[]> {
+  async findAll(userId: number, options: TodoListOptions = {}): Promise<TodoWithRelations[]> {
     // Todoを取得
     const todoList = await this.db
       .select()
       .from(todos)
-      .where(eq(todos.userId, userId))
-      .orderBy(asc(todos.position));
+      .where(listConditions(userId, options))
+      .orderBy(desc(todos.pinned), asc(todos.position));
+
+    return await this.attachRelations(todoList);
+  }
+
+  /**
+   * ユーザーのTodo一覧の件数を取得する（findAll と同じ条件で数える）
+   * @param userId - ユーザーID
+   * @param options - 一覧取得オプション
+   * @returns 件数
+   */
+  async countAll(userId: number, options: TodoListOptions = {}): Promise<number> {
+    const [result] = await this.db
+      .select({ count: count() })
+      .from(todos)
+      .where(listConditions(userId, options));
+    return result?.count ?? 0;
+  }
+
+  /**
+   * 指定日のアジェンダ対象のTodoを取得する（期限日順）
+   * 期限日が指定日以前のTodo（オプションで指定日にスヌーズが明けるTodo）を対象とする
+   * @param userId - ユーザーID
+   * @param today - 基準日（YYYY-MM-DD）
+   * @param options - アジェンダ取得オプション
+   * @returns TodoWithRelationsの配列
+   */
+  async findAgenda(
+    userId: number,
+    today: string,
+    options: AgendaOptions = {},
+  ): Promise<TodoWithRelations[]> {
+    const dueCondition = options.includeSnoozed
+      ? or(lte(todos.dueDate, today), eq(todos.snoozedUntil, today))
+      : lte(todos.dueDate, today);
+
+    const conditions: (SQL | undefined)[] = [
+      eq(todos.userId, userId),
+      archivedCondition(false),
+      notSnoozedCondition(today),
+      dueCondition,
+    ];
+
+    // 完了済みのTodoはデフォルトで除外
+    if (!options.includeCompleted) {
+      conditions.push(ne(todos.status, TODO.STATUS_MAP.completed));
+    }
+
+    const todoList = await this.db
+      .select()
+      .from(todos)
+      .where(and(...conditions))
+      .orderBy(sql`${todos.dueDate} ASC NULLS LAST`, asc(todos.position));
+
+    return await this.attachRelations(todoList);
+  }
+
+  /**
+   * リマインダーの通知時刻が指定期間内に入るTodoを取得する（通知時刻順）
+   * 通知時刻は期限日の0時（指定タイムゾーン）から reminder_offset_minutes 分前とする
+   * 完了済み・アーカイブ済みのTodoは対象外とする
+   * @param userId - ユーザーID
+   * @param options - リマインダー取得オプション
+   * @returns TodoWithRelationsの配列
+   */
+  async findReminders(userId: number, options: ReminderOptions): Promise<TodoWithRelations[]> {
+    const { from, to, timeZone } = options;
+    const remindAt = remindAtExpression(timeZone);
+
+    const todoList = await this.db
+      .select()
+      .from(todos)
+      .where(
+        and(
+          eq(todos.userId, userId),
+          archivedCondition(false),
+          ne(todos.status, TODO.STATUS_MAP.completed),
+          sql`${remindAt} >= ${from.toISOString()}::timestamptz`,
+          sql`${remindAt} < ${to.toISOString()}::timestamptz`,
+        ),
+      )
+      .orderBy(asc(remindAt), asc(todos.position));
+
+    return await this.attachRelations(todoList);
+  }
+
+  /**
+   * 通知時刻を過ぎ、まだ通知していないTodoを全ユーザーから取得し、通知済みにして確保する
+   * 取得と更新を1つのUPDATEで行い、他のインスタンスがロック中の行はSKIP LOCKEDで飛ばすため、
+   * 複数のインスタンスで通知ジョブを動かしても同じTodoを重複して通知しない
+   * 期限日が終わったTodo・完了済み・アーカイブ済み・再送待ちのTodoは対象外とする
+   * updated_at はユーザーによる更新ではないため変更しない
+   * @param now - 基準日時（通知日時として記録する）
+   * @param timeZone - 期限日の0時を判定するタイムゾーン
+   * @param limit - 最大件数（通知時刻の早い順に確保する）
+   * @returns 確保したTodoの配列
+   */
+  async claimDueReminders(now: Date, timeZone: string, limit: number): Promise<Todo[]> {
+    const current = sql`${now.toISOString()}::timestamptz`;
+    const remindAt = remindAtExpression(timeZone);
+
+    const claimable = this.db
+      .select({ id: todos.id })
+      .from(todos)
+      .where(
+        and(
+          isNull(todos.reminderSentAt),
+          or(isNull(todos.reminderRetryAt), lte(todos.reminderRetryAt, now)),
+          archivedCondition(false),
+          ne(todos.status, TODO.STATUS_MAP.completed),
+          sql`${remindAt} <= ${current}`,
+          // 期限日が終わったTodoは通知しない（有効化した直後に過去の分をまとめて送らないため）
+          sql`${dueAtExpression(timeZone)} + interval '1 day' > ${current}`,
+        ),
+      )
+      .orderBy(asc(remindAt), asc(todos.id))
+      .limit(limit)
+      .for("update", { skipLocked: true });
+
+    return await this.db
+      .update(todos)
+      .set({ reminderSentAt: now })
+      .where(inArray(todos.id, claimable))
+      .returning();
+  }
 
+  /**
+   * リマインダーの通知失敗を記録する
+   * 再送する場合は通知済みを取り消し、retryAt まで確保の対象から外す
+   * updated_at はユーザーによる更新ではないため変更しない
+   * @param id - TodoのID
+   * @param retryAt - 次回の再送日時（nullの場合は再送せず、通知済みのままにする）
+   */
+  async recordReminderFailure(id: number, retryAt: Date | null): Promise<void> {
+    await this.db
+      .update(todos)
+      .set({
+        reminderAttempts: sql`${todos.reminderAttempts} + 1`,
+        reminderRetryAt: retryAt,
+        ...(retryAt ? { reminderSentAt: null } : {}),
+      })
+      .where(eq(todos.id, id));
+  }
+
+  /**
+   * 作成数・完了数を集計単位（日・週）ごとに集計する
+   * 日時は指定タイムゾーンの日付に変換してから集計する
+   * @param userId - ユーザーID
+   * @param options - 推移取得オプション
+   * @returns 集計単位の開始日ごとの件数（件数0の集計単位は含まない）
+   */
+  async countTrends(userId: number, options: Required<TrendOptions>): Promise<TodoTrendCounts> {
+    const { from, to, bucket, timeZone } = options;
+
+    const countBy = async (column: typeof todos.createdAt | typeof todos.completedAt) => {
+      // 日時はUTCで保存しているため、UTCとして解釈してから指定タイムゾーンの日時に変換する
+      const localTime = sql`(${column} AT TIME ZONE 'UTC' AT TIME ZONE ${timeZone})`;
+      const rows = await this.db
+        .select({
+          date: sql<string>`to_char(date_trunc(${bucket}, ${localTime}), 'YYYY-MM-DD')`,
+          count: sql<number>`count(*)::int`,
+        })
+        .from(todos)
+        .where(
+          and(
+            eq(todos.userId, userId),
+            sql`${localTime} >= ${from}::date`,
+            sql`${localTime} < ${to}::date + 1`,
+          ),
+        )
+        // 式にパラメータを含むため、SELECT句の式を位置で指定してグループ化する
+        .groupBy(sql`1`);
+      return new Map(rows.map((row) => [row.date, row.count]));
+    };
+
+    const [created, completed] = await Promise.all([
+      countBy(todos.createdAt),
+      countBy(todos.completedAt),
+    ]);
+    return { created, completed };
+  }
+
+  /**
+   * Todoにカテゴリ・タグ・ラベルを結合する
+   * @param todoList - Todoの配列
+   * @returns TodoWithRelationsの配列
+   */
+  private async attachRelations(todoList: Todo[]): Promise<TodoWithRelations[]> {
     if (todoList.length === 0) {
       return [];
     }
@@ -150,11 +522,30 @@ export class TodoRepository implements TodoRepositoryInterface {
       tagsMap.set(row.todoId, existing);
     }
 
+    // TodoLabelとLabelを結合して取得
+    const labelResults = await this.db
+      .select({
+        todoId: todoLabels.todoId,
+        label: labels,
+      })
+      .from(todoLabels)
+      .innerJoin(labels, eq(todoLabels.labelId, labels.id))
+      .where(inArray(todoLabels.todoId, todoIds));
+
+    // Todoごとのラベルをマップに整理
+    const labelsMap = new Map<number, Label[]>();
+    for (const row of labelResults) {
+      const existing = labelsMap.get(row.todoId) ?? [];
+      existing.push(row.label);
+      labelsMap.set(row.todoId, existing);
+    }
+
     // 結果を組み立て
     return todoList.map((todo) => ({
       todo,
       category: todo.categoryId ? (categoryMap.get(todo.categoryId) ?? null) : null,
       tags: tagsMap.get(todo.id) ?? [],
+      labels: labelsMap.get(todo.id) ?? [],
     }));
   }
 
@@ -190,13 +581,37 @@ export class TodoRepository implements TodoRepositoryInterface {
       .innerJoin(tags, eq(todoTags.tagId, tags.id))
       .where(eq(todoTags.todoId, id));
 
+    // ラベルを取得（1クエリ）
+    const labelResults = await this.db
+      .select({
+        label: labels,
+      })
+      .from(todoLabels)
+      .innerJoin(labels, eq(todoLabels.labelId, labels.id))
+      .where(eq(todoLabels.todoId, id));
+
     return {
       todo: row.todo,
       category: row.category,
       tags: tagResults.map((r) => r.tag),
+      labels: labelResults.map((r) => r.label),
     };
   }
 
+  /**
+   * IDでTodoが存在するかを確認する（所有者を問わない）
+   * @param id - TodoのID
+   * @returns 存在する場合true
+   */
+  async existsById(id: number): Promise<boolean> {
+    const result = await this.db
+      .select({ id: todos.id })
+      .from(todos)
+      .where(eq(todos.id, id))
+      .limit(1);
+    return result.length > 0;
+  }
+
   /**
    * 複数のIDとユーザーIDでTodoを取得する
    * @param ids - TodoのIDの配列
@@ -213,6 +628,16 @@ export class TodoRepository implements TodoRepositoryInterface {
       .where(and(inArray(todos.id, ids), eq(todos.userId, userId)));
   }
 
+  /**
+   * 複数のIDとユーザーIDでTodoを取得する（リレーション含む）
+   * @param ids - TodoのIDの配列
+   * @param userId - ユーザーID
+   * @returns TodoWithRelationsの配列（順序は保証しない）
+   */
+  async findByIdsWithRelations(ids: number[], userId: number): Promise<TodoWithRelations[]> {
+    return await this.attachRelations(await this.findByIds(ids, userId));
+  }
+
   /**
    * Todoを作成する
    * @param data - 作成データ
@@ -264,6 +689,39 @@ export class TodoRepository implements TodoRepositoryInterface {
     return result.length > 0;
   }
 
+  /**
+   * ユーザーの完了済みTodoをすべて削除する
+   * @param userId - ユーザーID
+   * @returns 削除したTodoの配列
+   */
+  async deleteCompleted(userId: number): Promise<Todo[]> {
+    return await this.db
+      .delete(todos)
+      .where(and(eq(todos.userId, userId), eq(todos.status, TODO.STATUS_MAP.completed)))
+      .returning();
+  }
+
+  /**
+   * ユーザーの完了済みで未アーカイブのTodoをすべてアーカイブする
+   * @param userId - ユーザーID
+   * @returns アーカイブした件数
+   */
+  async archiveCompleted(userId: number): Promise<number> {
+    const now = new Date();
+    const result = await this.db
+      .update(todos)
+      .set({ archivedAt: now, updatedAt: now })
+      .where(
+        and(
+          eq(todos.userId, userId),
+          eq(todos.status, TODO.STATUS_MAP.completed),
+          archivedCondition(false),
+        ),
+      )
+      .returning({ id: todos.id });
+    return result.length;
+  }
+
   /**
    * ユーザーの最大positionを取得する
    * @param userId - ユーザーID
@@ -299,4 +757,157 @@ export class TodoRepository implements TodoRepositoryInterface {
       }
     });
   }
+
+  /**
+   * 指定position以降のTodoのpositionをずらす
+   * 一括で加算するため、対象のTodo同士の並び順と間隔は変わらない
+   * 並び順の調整はユーザーによる編集ではないため、updatedAtは更新しない
+   * @param userId - ユーザーID
+   * @param from - このposition以上のTodoを対象とする
+   * @param amount - 加算する値
+   */
+  async shiftPositions(userId: number, from: number, amount: number): Promise<void> {
+    await this.db
+      .update(todos)
+      .set({ position: sql`${todos.position} + ${amount}` })
+      .where(and(eq(todos.userId, userId), gte(todos.position, from)));
+  }
+
+  /**
+   * Todoを基準のTodoと、その隣のTodoの間に移動する
+   * 間にpositionの空きがあれば移動するTodoのみを更新し、空きがない場合は
+   * ユーザーのTodo全体のpositionを振り直してから移動する
+   * @param userId - ユーザーID
+   * @param id - 移動するTodoのID
+   * @param anchorId - 基準のTodoのID
+   * @param placement - 基準のTodoの直後（after）か直前（before）か
+   */
+  async insertBetween(
+    userId: number,
+    id: number,
+    anchorId: number,
+    placement: TodoInsertPlacement,
+  ): Promise<void> {
+    let position = await this.findInsertPosition(userId, id, anchorId, placement);
+    if (position === null) {
+      await this.renumberPositions(userId);
+      position = await this.findInsertPosition(userId, id, anchorId, placement);
+    }
+    // 振り直し後は必ず空きがあるため、ここでnullになるのは基準のTodoが存在しない場合のみ
+    if (position === null) {
+      return;
+    }
+
+    await this.db
+      .update(todos)
+      .set({ position, updatedAt: new Date() })
+      .where(and(eq(todos.id, id), eq(todos.userId, userId)));
+  }
+
+  /**
+   * 基準のTodoと、その隣のTodoの間の空いているpositionを求める
+   * 基準のTodoと同じpositionのTodoも隣として扱い、その場合は空きなしとする
+   * @param userId - ユーザーID
+   * @param id - 移動するTodoのID（隣の判定から除外する）
+   * @param anchorId - 基準のTodoのID
+   * @param placement - 基準のTodoの直後（after）か直前（before）か
+   * @returns 挿入するposition（空きがない場合はnull）
+   */
+  private async findInsertPosition(
+    userId: number,
+    id: number,
+    anchorId: number,
+    placement: TodoInsertPlacement,
+  ): Promise<number | null> {
+    const [anchor] = await this.db
+      .select({ position: todos.position })
+      .from(todos)
+      .where(and(eq(todos.id, anchorId), eq(todos.userId, userId)))
+      .limit(1);
+    if (!anchor || anchor.position === null) {
+      return null;
+    }
+
+    const others = and(eq(todos.userId, userId), ne(todos.id, id), ne(todos.id, anchorId));
+
+    if (placement === "after") {
+      const [next] = await this.db
+        .select({ position: min(todos.position) })
+        .from(todos)
+        .where(and(others, gte(todos.position, anchor.position)));
+      const nextPosition = next?.position ?? null;
+      return nextPosition === null
+        ? anchor.position + TODO.POSITION_GAP
+        : positionBetween(anchor.position, nextPosition);
+    }
+
+    // 先頭への挿入でもpositionが負にならないよう、前がない場合は -1 との間に挿入する
+    const [prev] = await this.db
+      .select({ position: max(todos.position) })
+      .from(todos)
+      .where(and(others, lte(todos.position, anchor.position)));
+    return positionBetween(prev?.position ?? -1, anchor.position);
+  }
+
+  /**
+   * ユーザーのTodo全体のpositionを、現在の並び順のまま POSITION_GAP 間隔で振り直す
+   * 先頭の前にも挿入できるよう POSITION_GAP から始める
+   * 並び順の調整はユーザーによる編集ではないため、updatedAtは更新しない
+   * @param userId - ユーザーID
+   */
+  private async renumberPositions(userId: number): Promise<void> {
+    const ranked = this.db
+      .select({
+        id: todos.id,
+        rank: sql<number>`row_number() over (
+          order by ${todos.position} asc nulls last, ${todos.id} asc
+        )`.as("rank"),
+      })
+      .from(todos)
+      .where(eq(todos.userId, userId))
+      .as("ranked");
+
+    await this.db
+      .update(todos)
+      .set({ position: sql`${ranked.rank} * ${TODO.POSITION_GAP}` })
+      .from(ranked)
+      .where(eq(todos.id, ranked.id));
+  }
+
+  /**
+   * 一覧（position順）で前後に並ぶTodoのIDを取得する
+   * 対象のTodoと同じアーカイブ状態で、スヌーズ中でないTodoの中から探す
+   * 全件を読み込まず、前後それぞれ1件だけを (ピン留めでない, position, id) の順で取得する
+   * @param todo - 基準のTodo
+   * @returns 前後のTodoのID（存在しない場合はnull）
+   */
+  async findNeighborIds(todo: Todo): Promise<TodoNeighborIds> {
+    if (todo.position === null) {
+      return { prevId: null, nextId: null };
+    }
+
+    const conditions = and(
+      eq(todos.userId, todo.userId),
+      archivedCondition(todo.archivedAt !== null),
+      notSnoozedCondition(todayIn()),
+    );
+    // ピン留めしたTodoが先頭に並ぶため、(ピン留めでない, position, id) の順で比較する
+    const current = sql`(${!todo.pinned}, ${todo.position}, ${todo.id})`;
+    const key = sql`(not ${todos.pinned}, ${todos.position}, ${todos.id})`;
+
+    const [prev] = await this.db
+      .select({ id: todos.id })
+      .from(todos)
+      .where(and(conditions, sql`${key} < ${current}`))
+      .orderBy(asc(todos.pinned), desc(todos.position), desc(todos.id))
+      .limit(1);
+    const [next] = await this.db
+      .select({ id: todos.id })
+      .from(todos)
+      .where(and(conditions, sql`${key} > ${current}`))
+      .orderBy(desc(todos.pinned), asc(todos.position), asc(todos.id))
+      .limit(1);
+
+    return { prevId: prev?.id ?? null, nextId: next?.id ?? null };
+  }
 }
@@ -3,7 +3,7 @@
  * @module features/todo/todo-tag-repository
  */
 
-import { eq } from "drizzle-orm";
+import { and, eq, inArray } from "drizzle-orm";
 import type { DatabaseOrTransaction } from "../../lib/db";
 import { todoTags } from "../../models/schema";
 
@@ -23,6 +23,22 @@ export interface TodoTagRepositoryInterface {
    * @param todoId - TodoのID
    */
   deleteByTodoId(todoId: number): Promise<void>;
+
+  /**
+   * 複数のTodoに複数のタグを追加する（既存の紐付けはスキップ）
+   * @param todoIds - TodoのIDの配列
+   * @param tagIds - タグIDの配列
+   * @returns 新たに追加された紐付けの数
+   */
+  addTags(todoIds: number[], tagIds: number[]): Promise<number>;
+
+  /**
+   * 複数のTodoから複数のタグを外す
+   * @param todoIds - TodoのIDの配列
+   * @param tagIds - タグIDの配列
+   * @returns 削除された紐付けの数
+   */
+  removeTags(todoIds: number[], tagIds: number[]): Promise<number>;
 }
 
 /**
@@ -61,4 +77,40 @@ export class TodoTagRepository implements TodoTagRepositoryInterface {
   async deleteByTodoId(todoId: number): Promise<void> {
     await this.db.delete(todoTags).where(eq(todoTags.todoId, todoId));
   }
+
+  /**
+   * 複数のTodoに複数のタグを追加する（既存の紐付けはスキップ）
+   * @param todoIds - TodoのIDの配列
+   * @param tagIds - タグIDの配列
+   * @returns 新たに追加された紐付けの数
+   */
+  async addTags(todoIds: number[], tagIds: number[]): Promise<number> {
+    if (todoIds.length === 0 || tagIds.length === 0) {
+      return 0;
+    }
+    const values = todoIds.flatMap((todoId) => tagIds.map((tagId) => ({ todoId, tagId })));
+    const inserted = await this.db
+      .insert(todoTags)
+      .values(values)
+      .onConflictDoNothing({ target: [todoTags.todoId, todoTags.tagId] })
+      .returning({ id: todoTags.id });
+    return inserted.length;
+  }
+
+  /**
+   * 複数のTodoから複数のタグを外す
+   * @param todoIds - TodoのIDの配列
+   * @param tagIds - タグIDの配列
+   * @returns 削除された紐付けの数
+   */
+  async removeTags(todoIds: number[], tagIds: number[]): Promise<number> {
+    if (todoIds.length === 0 || tagIds.length === 0) {
+      return 0;
+    }
+    const deleted = await this.db
+      .delete(todoTags)
+      .where(and(inArray(todoTags.todoId, todoIds), inArray(todoTags.tagId, tagIds)))
+      .returning({ id: todoTags.id });
+    return deleted.length;
+  }
 }
@@ -4,24 +4,114 @@
  */
 
 import { TODO } from "../../lib/constants";
-import type { Category, NewTodo, Tag, Todo } from "../../models/schema";
-import type { CategoryRef, TagRef, TodoResponse } from "../../shared/validators/responses";
+import type { Category, Label, NewTodo, Tag, Todo } from "../../models/schema";
+import type {
+  CategoryRef,
+  LabelRef,
+  TagRef,
+  TodoResponse,
+} from "../../shared/validators/responses";
 
 // 型はresponses.tsから再エクスポート
 export type {
+  AgendaResponse,
+  ArchiveCompletedTodosResponse,
+  BulkTagsResponse,
   CategoryRef,
+  DeleteCompletedTodosResponse,
+  LabelRef,
   TagRef,
   TodoResponse,
+  TodoTrendsResponse,
+  TodoWithNeighborsResponse,
 } from "../../shared/validators/responses";
 
 /** Todo更新データ型（userIdを除く部分更新用） */
 export type TodoUpdateData = Partial<Omit<NewTodo, "userId">>;
 
+/** Todo一覧取得オプション */
+export interface TodoListOptions {
+  /** スヌーズ中のTodoを含めるか（デフォルト: false） */
+  includeSnoozed?: boolean;
+  /** アーカイブ済みのTodoを取得するか（デフォルト: false = 未アーカイブのみ） */
+  archived?: boolean;
+}
+
+/** アジェンダ取得オプション */
+export interface AgendaOptions {
+  /** 完了済みのTodoを含めるか（デフォルト: false） */
+  includeCompleted?: boolean;
+  /** 今日スヌーズが明けるTodoを含めるか（デフォルト: false） */
+  includeSnoozed?: boolean;
+}
+
+/** リマインダー取得オプション */
+export interface ReminderOptions {
+  /** 対象期間の開始日時（この日時を含む） */
+  from: Date;
+  /** 対象期間の終了日時（この日時を含まない） */
+  to: Date;
+  /** 期限日の0時を判定するタイムゾーン */
+  timeZone: string;
+}
+
+/** 推移の集計単位 */
+export type TrendBucket = "day" | "week";
+
+/** 推移取得オプション */
+export interface TrendOptions {
+  /** 集計期間の開始日（YYYY-MM-DD、この日を含む） */
+  from: string;
+  /** 集計期間の終了日（YYYY-MM-DD、この日を含む） */
+  to: string;
+  /** 集計単位 */
+  bucket: TrendBucket;
+  /** 日付を判定するタイムゾーン（省略時はサーバーのタイムゾーン） */
+  timeZone?: string;
+}
+
+/** 集計単位ごとの作成数・完了数（キーは集計単位の開始日） */
+export interface TodoTrendCounts {
+  /** 作成されたTodoの件数 */
+  created: Map<string, number>;
+  /** 完了したTodoの件数 */
+  completed: Map<string, number>;
+}
+
+/** 冪等キー付きTodo作成の結果 */
+export interface CreateTodoResult {
+  /** 作成されたTodo */
+  todo: TodoResponse;
+  /** 同じ冪等キーによる再送で、作成済みのTodoを返したか */
+  replayed: boolean;
+}
+
+/** 基準のTodoに対する挿入位置 */
+export type TodoInsertPlacement = "after" | "before";
+
+/** 一覧で前後に並ぶTodoのID */
+export interface TodoNeighborIds {
+  /** 前のTodoのID */
+  prevId: number | null;
+  /** 次のTodoのID */
+  nextId: number | null;
+}
+
 /** DBから取得したTodoとリレーション */
 export interface TodoWithRelations {
   todo: Todo;
   category: Category | null;
   tags: Tag[];
+  labels: Label[];
+}
+
+/**
+ * Todoがアーカイブ済みかどうかを判定する
+ * @param todo - Todoエンティティ
+ * @returns アーカイブ済みの場合true
+ */
+export function isArchived(todo: Todo): boolean {
+  return todo.archivedAt !== null;
 }
 
 /**
@@ -50,6 +140,22 @@ export function statusToString(status: number): "pending" | "in_progress" | "com
   return value;
 }
 
+/**
+ * 優先度ごとの既定の色（#RRGGBB）
+ * PRIORITY_COLOR_LOW / PRIORITY_COLOR_MEDIUM / PRIORITY_COLOR_HIGH から作成される
+ */
+export type PriorityColors = Record<ReturnType<typeof priorityToString>, string>;
+
+/**
+ * 優先度に対応する既定の色を取得する
+ * @param priority - 優先度（0, 1, 2）
+ * @param colors - 優先度ごとの既定の色
+ * @returns 色コード（#RRGGBB）
+ */
+export function priorityColor(priority: number, colors: PriorityColors): string {
+  return colors[priorityToString(priority)];
+}
+
 /**
  * CategoryをCategoryRefに変換
  * @param category - カテゴリエンティティ
@@ -76,24 +182,50 @@ export function formatTagRef(tag: Tag): TagRef {
   };
 }
 
+/**
+ * LabelをLabelRefに変換
+ * @param label - ラベルエンティティ
+ * @returns ラベル参照
+ */
+export function formatLabelRef(label: Label): LabelRef {
+  return {
+    id: label.id,
+    name: label.name,
+    color: label.color,
+  };
+}
+
 /**
  * DBエンティティをAPIレスポンスに変換
  * @param data - Todoとリレーション
+ * @param priorityColors - カテゴリ未設定の場合に使う優先度ごとの既定の色
  * @returns Todoレスポンス
  */
-export function formatTodoResponse(data: TodoWithRelations): TodoResponse {
-  const { todo, category, tags } = data;
+export function formatTodoResponse(
+  data: TodoWithRelations,
+  priorityColors: PriorityColors,
+): TodoResponse {
+  const { todo, category, tags, labels } = data;
   return {
     id: todo.id,
     title: todo.title,
     completed: todo.completed ?? false,
+    starred: todo.starred,
+    pinned: todo.pinned,
     position: todo.position ?? 0,
     due_date: todo.dueDate,
+    reminder_offset_minutes: todo.reminderOffsetMinutes,
+    snoozed_until: todo.snoozedUntil,
+    archived_at: todo.archivedAt?.toISOString() ?? null,
+    completed_at: todo.completedAt?.toISOString() ?? null,
     priority: priorityToString(todo.priority),
     status: statusToString(todo.status),
     description: todo.description,
     category: category ? formatCategoryRef(category) : null,
+    // カテゴリ未設定の場合は優先度の既定色で代替する
+    effective_color: category?.color ?? priorityColor(todo.priority, priorityColors),
     tags: tags.map(formatTagRef),
+    labels: labels.map(formatLabelRef),
     created_at: todo.createdAt.toISOString(),
     updated_at: todo.updatedAt.toISOString(),
   };
@@ -5,6 +5,13 @@
 
 import { z } from "zod";
 import { TODO } from "../../lib/constants";
+import { todayIn } from "../../lib/date";
+import { TODO_ERROR_MESSAGES } from "../../shared/errors/messages";
+import {
+  booleanQuerySchema,
+  envelopeQuerySchema,
+  timeZoneSchema,
+} from "../../shared/validators/common";
 
 /** 優先度スキーマ */
 const prioritySchema = z.enum(["low", "medium", "high"], {
@@ -17,10 +24,20 @@ const statusSchema = z.enum(["pending", "in_progress", "completed"], {
 });
 
 /** 日付文字列スキーマ（YYYY-MM-DD形式） */
-const dueDateSchema = z
-  .string()
-  .regex(/^\d{4}-\d{2}-\d{2}$/, {
-    message: "日付はYYYY-MM-DD形式で入力してください",
+const dateStringSchema = z.string().regex(/^\d{4}-\d{2}-\d{2}$/, {
+  message: "日付はYYYY-MM-DD形式で入力してください",
+});
+
+/** 期限日スキーマ */
+const dueDateSchema = dateStringSchema.nullable().optional();
+
+/** リマインダーのオフセット（期限日の何分前に通知するか）スキーマ */
+const reminderOffsetMinutesSchema = z
+  .number()
+  .int({ message: "reminder_offset_minutes は整数で指定してください" })
+  .min(0, { message: "reminder_offset_minutes は0以上で指定してください" })
+  .max(TODO.REMINDER_OFFSET_MAX_MINUTES, {
+    message: `reminder_offset_minutes は${TODO.REMINDER_OFFSET_MAX_MINUTES}以下で指定してください`,
   })
   .nullable()
   .optional();
@@ -34,15 +51,23 @@ function hasNoDuplicates<T>(arr: T[]): boolean {
   return new Set(arr).size === arr.length;
 }
 
+/** TodoIDスキーマ */
+const todoIdSchema = z.number().int().positive({ message: "IDは正の整数である必要があります" });
+
 /** tag_ids スキーマ（重複チェック付き） */
 const tagIdsSchema = z.array(z.number().int().positive()).refine(hasNoDuplicates, {
   message: "tag_idsに重複するIDが含まれています",
 });
 
+/** label_ids スキーマ（重複チェック付き） */
+const labelIdsSchema = z.array(z.number().int().positive()).refine(hasNoDuplicates, {
+  message: "label_idsに重複するIDが含まれています",
+});
+
 /**
  * Todo作成スキーマ
  */
-export const createTodoSchema = z.object({
+export const createTodoSchema = z.strictObject({
   title: z
     .string({ message: "タイトルは必須です" })
     .min(1, { message: "タイトルは必須です" })
@@ -59,14 +84,21 @@ export const createTodoSchema = z.object({
   priority: prioritySchema.optional().default("medium"),
   status: statusSchema.optional().default("pending"),
   due_date: dueDateSchema,
+  reminder_offset_minutes: reminderOffsetMinutesSchema,
   category_id: z.number().int().positive().nullable().optional(),
   tag_ids: tagIdsSchema.optional().default([]),
+  label_ids: labelIdsSchema.optional().default([]),
+  position: z
+    .number()
+    .int({ message: "position は整数で指定してください" })
+    .nonnegative({ message: "position は0以上で指定してください" })
+    .optional(),
 });
 
 /**
  * Todo更新スキーマ
  */
-export const updateTodoSchema = z.object({
+export const updateTodoSchema = z.strictObject({
   title: z
     .string()
     .min(1, { message: "タイトルは空にできません" })
@@ -85,17 +117,21 @@ export const updateTodoSchema = z.object({
   priority: prioritySchema.optional(),
   status: statusSchema.optional(),
   due_date: dueDateSchema,
+  reminder_offset_minutes: reminderOffsetMinutesSchema,
   category_id: z.number().int().positive().nullable().optional(),
   tag_ids: tagIdsSchema.optional(),
+  label_ids: labelIdsSchema.optional(),
+  archived: z.boolean().optional(),
+  pinned: z.boolean().optional(),
 });
 
 /**
  * 順序更新スキーマ
  */
-export const updateOrderSchema = z.object({
+export const updateOrderSchema = z.strictObject({
   todos: z
     .array(
-      z.object({
+      z.strictObject({
         id: z.number().int().positive({ message: "IDは正の整数である必要があります" }),
         position: z.number().int().min(0, { message: "positionは0以上である必要があります" }),
       }),
@@ -106,6 +142,154 @@ export const updateOrderSchema = z.object({
     }),
 });
 
+/**
+ * Todo移動スキーマ
+ * after_id / before_id のいずれか一方を指定する
+ */
+export const moveTodoSchema = z
+  .strictObject({
+    after_id: todoIdSchema.optional(),
+    before_id: todoIdSchema.optional(),
+  })
+  .refine((data) => (data.after_id === undefined) !== (data.before_id === undefined), {
+    message: TODO_ERROR_MESSAGES.MOVE_TARGET_REQUIRED,
+    path: ["after_id"],
+  });
+
+/**
+ * タグ一括付け外しスキーマ
+ */
+export const bulkTagsSchema = z
+  .strictObject({
+    todo_ids: z
+      .array(z.number().int().positive({ message: "IDは正の整数である必要があります" }))
+      .min(1, { message: "少なくとも1つのTodoを指定してください" })
+      .refine(hasNoDuplicates, { message: "todo_idsに重複するIDが含まれています" }),
+    add_tag_ids: tagIdsSchema.optional().default([]),
+    remove_tag_ids: tagIdsSchema.optional().default([]),
+  })
+  .refine((data) => data.add_tag_ids.length > 0 || data.remove_tag_ids.length > 0, {
+    message: "add_tag_ids または remove_tag_ids のいずれかを指定してください",
+    path: ["add_tag_ids"],
+  })
+  .refine((data) => !data.add_tag_ids.some((id) => data.remove_tag_ids.includes(id)), {
+    message: "同じタグを追加と削除の両方に指定することはできません",
+    path: ["remove_tag_ids"],
+  });
+
+/**
+ * スヌーズスキーマ
+ * until に null を指定するとスヌーズを解除する
+ * スヌーズ中のTodoは until の前日まで一覧から除外されるため、until は明日以降（サーバーのタイムゾーン）に限る
+ */
+export const snoozeTodoSchema = z.strictObject({
+  until: dateStringSchema
+    .refine((until) => until > todayIn(), { message: TODO_ERROR_MESSAGES.SNOOZE_UNTIL_NOT_FUTURE })
+    .nullable(),
+});
+
+/**
+ * Todo一覧クエリスキーマ
+ */
+export const listTodoQuerySchema = envelopeQuerySchema.extend({
+  include_snoozed: booleanQuerySchema.optional(),
+  archived: booleanQuerySchema.optional(),
+});
+
+/**
+ * アジェンダクエリスキーマ
+ */
+export const agendaQuerySchema = z.object({
+  tz: timeZoneSchema.optional(),
+  include_completed: booleanQuerySchema.optional(),
+  include_snoozed: booleanQuerySchema.optional(),
+});
+
+/**
+ * Todo推移クエリスキーマ
+ * from〜to（両端を含む）の期間を bucket 単位で集計する。期間は TRENDS_MAX_DAYS 日以内に制限する
+ */
+export const trendsQuerySchema = z
+  .object({
+    from: dateStringSchema,
+    to: dateStringSchema,
+    bucket: z
+      .enum(["day", "week"], { message: "bucket は day, week のいずれかを指定してください" })
+      .default("day"),
+    tz: timeZoneSchema.optional(),
+  })
+  .refine((data) => data.from <= data.to, {
+    message: "from は to 以前の日付を指定してください",
+    path: ["from"],
+  })
+  .refine(
+    (data) => (Date.parse(data.to) - Date.parse(data.from)) / 86400000 < TODO.TRENDS_MAX_DAYS,
+    {
+      message: `期間は${TODO.TRENDS_MAX_DAYS}日以内で指定してください`,
+      path: ["to"],
+    },
+  );
+
+/**
+ * Todo詳細クエリスキーマ
+ * context=position の場合は一覧で前後に並ぶTodoのID（prev_id / next_id）を含める
+ */
+export const showTodoQuerySchema = z.object({
+  context: z
+    .enum(["position"], { message: "context は position を指定してください" })
+    .optional(),
+});
+
+/**
+ * Todo一括取得クエリスキーマ
+ * ids はカンマ区切りのTodoID（例: ids=1,2,3）
+ */
+export const batchQuerySchema = z.object({
+  ids: z
+    .string({ message: "idsを指定してください" })
+    .transform((val) => val.split(",").map((id) => Number(id.trim())))
+    .pipe(
+      z
+        .array(z.number().int().positive({ message: "IDは正の整数である必要があります" }))
+        .min(1, { message: "少なくとも1つのIDを指定してください" })
+        .max(TODO.BATCH_MAX_IDS, {
+          message: `IDは${TODO.BATCH_MAX_IDS}件以内で指定してください`,
+        }),
+    ),
+});
+
+/**
+ * Todo作成ヘッダースキーマ
+ * Idempotency-Key を指定すると同じキーでの再送時に最初のレスポンスを返す
+ */
+export const createTodoHeaderSchema = z.object({
+  "idempotency-key": z
+    .string()
+    .trim()
+    .min(1, { message: "Idempotency-Keyを入力してください" })
+    .max(TODO.IDEMPOTENCY_KEY_MAX_LENGTH, {
+      message: `Idempotency-Keyは${TODO.IDEMPOTENCY_KEY_MAX_LENGTH}文字以内で入力してください`,
+    })
+    .optional(),
+});
+
+/**
+ * Todo更新リクエストのヘッダースキーマ
+ * If-Unmodified-Since が日時として解釈できない場合は RFC 9110 に従い無視する
+ */
+export const updateTodoHeaderSchema = z.object({
+  "if-unmodified-since": z
+    .string()
+    .optional()
+    .transform((val) => {
+      if (val === undefined) {
+        return undefined;
+      }
+      const time = Date.parse(val);
+      return Number.isNaN(time) ? undefined : new Date(time);
+    }),
+});
+
 // IDパラメータスキーマは共通モジュールからre-export
 export { type IdParam, idParamSchema } from "../../shared/validators/common";
 
@@ -117,3 +301,21 @@ export type UpdateTodoInput = z.infer<typeof updateTodoSchema>;
 
 /** 順序更新入力型 */
 export type UpdateOrderInput = z.infer<typeof updateOrderSchema>;
+
+/** Todo移動入力型 */
+export type MoveTodoInput = z.infer<typeof moveTodoSchema>;
+
+/** タグ一括付け外し入力型 */
+export type BulkTagsInput = z.infer<typeof bulkTagsSchema>;
+
+/** スヌーズ入力型 */
+export type SnoozeTodoInput = z.infer<typeof snoozeTodoSchema>;
+
+/** アジェンダクエリ入力型 */
+export type AgendaQuery = z.infer<typeof agendaQuerySchema>;
+
+/** Todo推移クエリ入力型 */
+export type TrendsQuery = z.infer<typeof trendsQuerySchema>;
+
+/** Todo一覧クエリ入力型 */
+export type ListTodoQuery = z.infer<typeof listTodoQuerySchema>;
@@ -1,13 +1,61 @@
 import { serve } from "@hono/node-server";
+import { startDenylistCleanup } from "./features/auth/denylist-cleanup";
+import { startReminderDispatch } from "./features/reminder/dispatcher";
+import { createReminderNotifier } from "./features/reminder/notifier";
+import { startIdempotencyKeyCleanup } from "./features/todo/idempotency-key-cleanup";
 import { createApp } from "./lib/app";
 import { getConfig } from "./lib/config";
+import { TODO } from "./lib/constants";
+import {
+  getIdempotencyKeyRepository,
+  getJwtDenylistRepository,
+  getTodoRepository,
+} from "./lib/container";
+import { serverTimeZone } from "./lib/date";
 import { closeDb } from "./lib/db";
 
-const app = createApp({ enableLogger: true });
+const config = getConfig();
+const app = createApp({
+  enableLogger: true,
+  corsOrigins: config.CORS_ORIGINS,
+  enableCompression: config.COMPRESSION_ENABLED,
+  compressionThreshold: config.COMPRESSION_THRESHOLD_BYTES,
+  timestampFormat: config.API_TIME_FORMAT,
+});
+
+// Background jobs
+const stopDenylistCleanup = startDenylistCleanup(
+  getJwtDenylistRepository(),
+  config.DENYLIST_CLEANUP_INTERVAL_MINUTES * 60 * 1000,
+);
+const stopIdempotencyKeyCleanup = startIdempotencyKeyCleanup(
+  getIdempotencyKeyRepository(),
+  config.IDEMPOTENCY_KEY_CLEANUP_INTERVAL_MINUTES * 60 * 1000,
+);
+const stopReminderDispatch = config.REMINDER_DISPATCH_ENABLED
+  ? startReminderDispatch(
+      getTodoRepository(),
+      createReminderNotifier(
+        config.REMINDER_WEBHOOK_URL,
+        config.REMINDER_WEBHOOK_TIMEOUT_SECONDS * 1000,
+      ),
+      serverTimeZone(),
+      config.REMINDER_DISPATCH_INTERVAL_MINUTES * 60 * 1000,
+    )
+  : async () => {};
 
 // Graceful shutdown
 const shutdown = async () => {
   console.log("Shutting down...");
+  stopDenylistCleanup();
+  stopIdempotencyKeyCleanup();
+  // 通知中のリマインダーを通知済みにしてからDB接続を閉じる（完了しない場合も一定時間で打ち切る）
+  await Promise.race([
+    stopReminderDispatch(),
+    new Promise<void>((resolve) => {
+      setTimeout(resolve, TODO.REMINDER_DISPATCH_SHUTDOWN_TIMEOUT_MS).unref();
+    }),
+  ]);
   await closeDb();
   process.exit(0);
 };
@@ -16,7 +64,6 @@ process.on("SIGINT", shutdown);
 process.on("SIGTERM", shutdown);
 
 // Start server
-const config = getConfig();
 console.log(`Server starting on port ${config.PORT}...`);
 
 serve({
@@ -5,43 +5,107 @@
 
 import { Hono } from "hono";
 import { cors } from "hono/cors";
-import { logger } from "hono/logger";
 import { secureHeaders } from "hono/secure-headers";
+import type { Logger } from "pino";
 import authRoutes from "../features/auth/routes";
 import categoryRoutes from "../features/category/routes";
+import labelRoutes from "../features/label/routes";
+import metadataRoutes from "../features/metadata/routes";
+import reminderRoutes from "../features/reminder/routes";
 import tagRoutes from "../features/tag/routes";
 import todoRoutes from "../features/todo/routes";
-import { ApiError } from "./errors";
+import { compression } from "../shared/middleware/compression";
+import { jsonBody } from "../shared/middleware/json-body";
+import { requestLogger } from "../shared/middleware/request-logger";
+import { type TimestampFormat, timestampFormat } from "../shared/middleware/timestamp-format";
+import { createCorsOptions, DEFAULT_CORS_ORIGINS } from "./cors";
+import { ApiError, internalError, methodNotAllowed, routeNotFound } from "./errors";
+import { getLogger, rootLogger } from "./logger";
 
 /** アプリケーション作成オプション */
 export interface CreateAppOptions {
-  /** ロガーを有効にするか（デフォルト: false） */
+  /** リクエストの完了をログに出力するか（デフォルト: false） */
   enableLogger?: boolean;
+  /** ベースとなるロガー（デフォルト: ルートロガー） */
+  logger?: Logger;
+  /** CORSで許可するオリジン（デフォルト: http://localhost:3000） */
+  corsOrigins?: string[];
+  /** レスポンスを圧縮するか（デフォルト: false） */
+  enableCompression?: boolean;
+  /** 圧縮する最小バイト数（デフォルト: 1024） */
+  compressionThreshold?: number;
+  /** レスポンスのタイムスタンプ形式（デフォルト: rfc3339） */
+  timestampFormat?: TimestampFormat;
+}
+
+/** ルート定義（app.routes の要素） */
+interface RouteDefinition {
+  method: string;
+  path: string;
+}
+
+/**
+ * ルートパターンを正規表現に変換する
+ * @param pattern - ルートパターン（例: "/api/v1/todos/:id"）
+ * @returns パスにマッチする正規表現
+ */
+function routePatternToRegExp(pattern: string): RegExp {
+  const source = pattern
+    .split("/")
+    .map((segment) => {
+      if (segment === "*") return ".*";
+      if (segment.startsWith(":")) {
+        // :name{regex} 形式のパラメータはregex部分を使用する
+        const custom = segment.match(/^:[^{]+\{(.+)\}$/);
+        return custom ? `(?:${custom[1]})` : "[^/]+";
+      }
+      return segment.replace(/[.*+?^${}()|[\]\\]/g, "\\$&");
+    })
+    .join("/");
+  return new RegExp(`^${source}/?$`);
+}
+
+/**
+ * パスに一致するルートで許可されているHTTPメソッドを取得する
+ * ミドルウェア（ALL）は対象外とする
+ * @param routes - 登録済みのルート定義
+ * @param path - リクエストパス
+ * @returns 許可されているメソッドの配列
+ */
+function findAllowedMethods(routes: RouteDefinition[], path: string): string[] {
+  const methods = routes
+    .filter((route) => route.method !== "ALL" && routePatternToRegExp(route.path).test(path))
+    .map((route) => route.method);
+  return [...new Set(methods)];
 }
 
 /**
  * Honoアプリケーションを作成する
  * @param options - アプリケーション作成オプション
  * @returns 設定済みのHonoアプリケーション
+ * @throws CORS設定が不正な場合
  */
 export function createApp(options: CreateAppOptions = {}): Hono {
-  const { enableLogger = false } = options;
+  const {
+    enableLogger = false,
+    corsOrigins = DEFAULT_CORS_ORIGINS,
+    logger = rootLogger,
+    enableCompression = false,
+    compressionThreshold = 1024,
+    timestampFormat: timestampFormatOption = "rfc3339",
+  } = options;
 
   const app = new Hono();
 
   // Middleware
-  if (enableLogger) {
-    app.use("*", logger());
+  app.use("*", requestLogger({ logger, accessLog: enableLogger }));
+  if (enableCompression) {
+    app.use("*", compression({ threshold: compressionThreshold }));
   }
   app.use("*", secureHeaders());
-  app.use(
-    "*",
-    cors({
-      origin: ["http://localhost:3000"],
-      credentials: true,
-      exposeHeaders: ["Authorization"],
-    }),
-  );
+  app.use("*", cors(createCorsOptions(corsOrigins)));
+  app.use("*", timestampFormat({ format: timestampFormatOption }));
+  app.use("*", jsonBody());
 
   // Health check
   app.get("/health", (c) => {
@@ -56,6 +120,9 @@ export function createApp(options: CreateAppOptions = {}): Hono {
   api.route("/todos", todoRoutes);
   api.route("/categories", categoryRoutes);
   api.route("/tags", tagRoutes);
+  api.route("/labels", labelRoutes);
+  api.route("/metadata", metadataRoutes);
+  api.route("/reminders", reminderRoutes);
   app.route("/api/v1", api);
 
   // Error handler
@@ -64,29 +131,23 @@ export function createApp(options: CreateAppOptions = {}): Hono {
       return c.json(err.toJSON(), err.statusCode);
     }
 
-    console.error("Unhandled error:", err);
-    return c.json(
-      {
-        error: {
-          code: "INTERNAL_ERROR",
-          message: "内部エラーが発生しました",
-        },
-      },
-      500,
-    );
+    getLogger(c).error({ err }, "Unhandled error");
+    const error = internalError();
+    return c.json(error.toJSON(), error.statusCode);
   });
 
-  // 404 handler
+  // 404 / 405 handler
+  // パスは存在するがメソッドが一致しない場合は405、パス自体が存在しない場合は404を返す
   app.notFound((c) => {
-    return c.json(
-      {
-        error: {
-          code: "NOT_FOUND",
-          message: "リソースが見つかりません",
-        },
-      },
-      404,
-    );
+    const allowedMethods = findAllowedMethods(app.routes, c.req.path);
+    if (allowedMethods.length > 0) {
+      const error = methodNotAllowed(c.req.method);
+      c.header("Allow", allowedMethods.join(", "));
+      return c.json(error.toJSON(), error.statusCode);
+    }
+
+    const error = routeNotFound();
+    return c.json(error.toJSON(), error.statusCode);
   });
 
   return app;
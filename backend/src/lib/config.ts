@@ -1,18 +1,85 @@
 import { z } from "zod";
+import { hexColorRegex, normalizeHexColor } from "../shared/validators/common";
+import { VALIDATION } from "./constants";
 
-const envSchema = z.object({
-  DATABASE_URL: z.string().url(),
-  JWT_SECRET: z.string().min(32),
-  PORT: z.coerce.number().default(3000),
-  ENV: z.enum(["development", "production", "test"]).default("development"),
-  REDIS_URL: z.string().url().optional(),
-  S3_ENDPOINT: z.string().url(),
-  S3_REGION: z.string().default("us-east-1"),
-  S3_BUCKET: z.string().default("todo-files"),
-  S3_ACCESS_KEY: z.string(),
-  S3_SECRET_KEY: z.string(),
-  S3_USE_PATH_STYLE: z.coerce.boolean().default(true),
-});
+/**
+ * 優先度ごとの既定色スキーマ（#RRGGBB形式、#RGB 形式は #RRGGBB 形式に正規化する）
+ * @param fallback - 未設定時の色
+ */
+const priorityColorSchema = (fallback: string) =>
+  z.string().regex(hexColorRegex).transform(normalizeHexColor).default(fallback);
+
+const envSchema = z
+  .object({
+    DATABASE_URL: z.string().url(),
+    JWT_SECRET: z.string().min(32),
+    PORT: z.coerce.number().default(3000),
+    ENV: z.enum(["development", "production", "test"]).default("development"),
+    REDIS_URL: z.string().url().optional(),
+    S3_ENDPOINT: z.string().url(),
+    S3_REGION: z.string().default("us-east-1"),
+    S3_BUCKET: z.string().default("todo-files"),
+    S3_ACCESS_KEY: z.string(),
+    S3_SECRET_KEY: z.string(),
+    S3_USE_PATH_STYLE: z.coerce.boolean().default(true),
+    DENYLIST_CLEANUP_INTERVAL_MINUTES: z.coerce.number().int().positive().default(60),
+    IDEMPOTENCY_KEY_CLEANUP_INTERVAL_MINUTES: z.coerce.number().int().positive().default(60),
+    COMPRESSION_ENABLED: z
+      .enum(["true", "false"])
+      .default("true")
+      .transform((val) => val === "true"),
+    COMPRESSION_THRESHOLD_BYTES: z.coerce.number().int().nonnegative().default(1024),
+    DEFAULT_PAGE_SIZE: z.coerce.number().int().positive().default(20),
+    MAX_PAGE_SIZE: z.coerce.number().int().positive().default(100),
+    API_TIME_FORMAT: z.enum(["rfc3339", "unix"]).default("rfc3339"),
+    TAG_DEFAULT_COLOR_ENABLED: z
+      .enum(["true", "false"])
+      .default("true")
+      .transform((val) => val === "true"),
+    PRIORITY_COLOR_LOW: priorityColorSchema("#22C55E"),
+    PRIORITY_COLOR_MEDIUM: priorityColorSchema("#F59E0B"),
+    PRIORITY_COLOR_HIGH: priorityColorSchema("#EF4444"),
+    REMINDER_DISPATCH_ENABLED: z
+      .enum(["true", "false"])
+      .default("false")
+      .transform((val) => val === "true"),
+    REMINDER_DISPATCH_INTERVAL_MINUTES: z.coerce.number().int().positive().default(1),
+    REMINDER_WEBHOOK_URL: z.string().url().optional(),
+    REMINDER_WEBHOOK_TIMEOUT_SECONDS: z.coerce.number().int().positive().default(10),
+    HIDE_CROSS_USER_AS_404: z
+      .enum(["true", "false"])
+      .default("true")
+      .transform((val) => val === "true"),
+    PASSWORD_MIN_LENGTH: z.coerce
+      .number()
+      .int()
+      .min(VALIDATION.PASSWORD_MIN_LENGTH)
+      .max(VALIDATION.PASSWORD_MAX_LENGTH)
+      .default(VALIDATION.PASSWORD_MIN_LENGTH),
+    PASSWORD_REQUIRE_MIXED_CLASSES: z
+      .enum(["true", "false"])
+      .default("false")
+      .transform((val) => val === "true"),
+    CORS_ORIGINS: z
+      .string()
+      .default("http://localhost:3000")
+      .transform((val) =>
+        val
+          .split(",")
+          .map((origin) => origin.trim())
+          .filter((origin) => origin.length > 0),
+      ),
+  })
+  .superRefine((env, ctx) => {
+    // per_page省略時の既定値が上限を超えると、すべての一覧リクエストが自身の検証で失敗する
+    if (env.DEFAULT_PAGE_SIZE > env.MAX_PAGE_SIZE) {
+      ctx.addIssue({
+        code: z.ZodIssueCode.custom,
+        message: `DEFAULT_PAGE_SIZE must not exceed MAX_PAGE_SIZE (${env.MAX_PAGE_SIZE})`,
+        path: ["DEFAULT_PAGE_SIZE"],
+      });
+    }
+  });
 
 export type Env = z.infer<typeof envSchema>;
 
@@ -7,8 +7,8 @@
 export const AUTH = {
   /** bcryptのコスト係数 */
   BCRYPT_COST: 12,
-  /** JWTの有効期限 */
-  JWT_EXPIRES_IN: "24h",
+  /** JWTの有効期限（秒） */
+  JWT_EXPIRES_IN_SECONDS: 24 * 60 * 60,
   /** Bearer認証スキーム */
   BEARER_SCHEME: "Bearer ",
   /** Bearer認証スキームの長さ */
@@ -22,7 +22,7 @@ export const AUTH = {
 
 /** バリデーション関連の定数 */
 export const VALIDATION = {
-  /** パスワードの最小文字数 */
+  /** パスワードの最小文字数（PASSWORD_MIN_LENGTHで引き上げ可能） */
   PASSWORD_MIN_LENGTH: 8,
   /** パスワードの最大文字数（bcryptの制限） */
   PASSWORD_MAX_LENGTH: 72,
@@ -36,8 +36,36 @@ export const VALIDATION = {
 export const TODO = {
   /** タイトルの最大文字数 */
   TITLE_MAX_LENGTH: 255,
+  /** 一括取得で指定できるIDの最大数 */
+  BATCH_MAX_IDS: 100,
+  /** Idempotency-Key の最大文字数 */
+  IDEMPOTENCY_KEY_MAX_LENGTH: 255,
+  /** Idempotency-Key の有効期間（秒） */
+  IDEMPOTENCY_KEY_TTL_SECONDS: 24 * 60 * 60,
+  /** 冪等キーによる再送であることを示すレスポンスヘッダー */
+  IDEMPOTENT_REPLAYED_HEADER: "Idempotent-Replayed",
+  /** 一覧の件数を返すレスポンスヘッダー（HEADリクエスト用） */
+  TOTAL_COUNT_HEADER: "X-Total-Count",
   /** 説明の最大文字数 */
   DESCRIPTION_MAX_LENGTH: 10000,
+  /** 新規作成・振り直し時のpositionの間隔（間に挿入する際に他のTodoを更新せずに済むよう空けておく） */
+  POSITION_GAP: 1000,
+  /** 推移の集計で指定できる期間の最大日数 */
+  TRENDS_MAX_DAYS: 366,
+  /** リマインダーのオフセット（期限日の何分前に通知するか）の最大値（30日） */
+  REMINDER_OFFSET_MAX_MINUTES: 30 * 24 * 60,
+  /** リマインダー一覧の対象期間（分）のデフォルト値 */
+  REMINDER_WINDOW_DEFAULT_MINUTES: 60,
+  /** リマインダー一覧の対象期間（分）の最大値（7日） */
+  REMINDER_WINDOW_MAX_MINUTES: 7 * 24 * 60,
+  /** リマインダー通知ジョブが1回に処理する最大件数 */
+  REMINDER_DISPATCH_BATCH_SIZE: 100,
+  /** リマインダーの通知を試みる最大回数（超えた場合は再送しない） */
+  REMINDER_MAX_ATTEMPTS: 5,
+  /** リマインダーの通知に失敗した場合の再送間隔の基準（ミリ秒、失敗するたびに倍にする） */
+  REMINDER_RETRY_BASE_DELAY_MS: 60 * 1000,
+  /** サーバー終了時に実行中のリマインダー通知の完了を待つ最大時間（ミリ秒） */
+  REMINDER_DISPATCH_SHUTDOWN_TIMEOUT_MS: 15 * 1000,
 
   /** 優先度: 文字列 -> 整数 */
   PRIORITY_MAP: {
@@ -68,12 +96,47 @@ export type TodoStatus = keyof typeof TODO.STATUS_MAP;
 export const CATEGORY = {
   /** 名前の最大文字数 */
   NAME_MAX_LENGTH: 50,
+  /** カテゴリなしを表すID（検索の category_id=-1 と揃える） */
+  UNCATEGORIZED_ID: -1,
+  /** カテゴリ一覧に含める「カテゴリなし」の名前 */
+  UNCATEGORIZED_NAME: "uncategorized",
+  /** カテゴリ一覧に含める「カテゴリなし」の色 */
+  UNCATEGORIZED_COLOR: "#9CA3AF",
 } as const;
 
 /** タグ関連の定数 */
 export const TAG = {
   /** 名前の最大文字数 */
   NAME_MAX_LENGTH: 30,
+  /** 色を指定せずに作成したタグに割り当てる色の候補（名前から決定的に選ぶ） */
+  DEFAULT_COLORS: [
+    "#E74C3C",
+    "#E67E22",
+    "#F1C40F",
+    "#2ECC71",
+    "#1ABC9C",
+    "#3498DB",
+    "#9B59B6",
+    "#34495E",
+  ],
+} as const;
+
+/** ラベル関連の定数 */
+export const LABEL = {
+  /** 名前の最大文字数 */
+  NAME_MAX_LENGTH: 30,
+  /** 説明の最大文字数 */
+  DESCRIPTION_MAX_LENGTH: 255,
+} as const;
+
+/** 添付ファイルの紐付け先タイプ（files.attachable_type） */
+export const ATTACHABLE_TYPES = {
+  TODO: "Todo",
+} as const;
+
+/** コメント対象の種類（comments.commentable_type） */
+export const COMMENTABLE_TYPES = {
+  TODO: "Todo",
 } as const;
 
 /** リソース名（notFound等のエラーメッセージで使用） */
@@ -81,5 +144,6 @@ export const RESOURCE_NAMES = {
   TODO: "Todo",
   CATEGORY: "カテゴリ",
   TAG: "タグ",
+  LABEL: "ラベル",
   USER: "ユーザー",
 } as const;
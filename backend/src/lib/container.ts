@@ -9,15 +9,21 @@ import { AuthService } from "../features/auth/service";
 import { UserRepository } from "../features/auth/user-repository";
 import { CategoryRepository as CategoryCrudRepository } from "../features/category/repository";
 import { CategoryService } from "../features/category/service";
+import { LabelRepository as LabelCrudRepository } from "../features/label/repository";
+import { LabelService } from "../features/label/service";
 import { TagRepository as TagCrudRepository } from "../features/tag/repository";
 import { TagService } from "../features/tag/service";
+import { IdempotencyKeyRepository } from "../features/todo/idempotency-key-repository";
 import { TodoSearchRepository } from "../features/todo/search-repository";
 import { TodoSearchService } from "../features/todo/search-service";
 import { TodoService } from "../features/todo/service";
 import { TodoCategoryRepository } from "../features/todo/todo-category-repository";
+import { TodoLabelRepository } from "../features/todo/todo-label-repository";
 import { TodoRepository } from "../features/todo/todo-repository";
 import { TodoTagRepository } from "../features/todo/todo-tag-repository";
 import { TodoTagValidatorRepository } from "../features/todo/todo-tag-validator-repository";
+import type { PriorityColors } from "../features/todo/types";
+import { getConfig } from "./config";
 import { type DatabaseOrTransaction, getDb } from "./db";
 
 // ============================================
@@ -76,6 +82,10 @@ export interface RepositoryFactories {
   createTagValidatorRepository: (db: DatabaseOrTransaction) => TodoTagValidatorRepository;
   /** TodoTagRepositoryを作成する */
   createTodoTagRepository: (db: DatabaseOrTransaction) => TodoTagRepository;
+  /** TodoLabelRepositoryを作成する */
+  createTodoLabelRepository: (db: DatabaseOrTransaction) => TodoLabelRepository;
+  /** IdempotencyKeyRepositoryを作成する */
+  createIdempotencyKeyRepository: (db: DatabaseOrTransaction) => IdempotencyKeyRepository;
 }
 
 /**
@@ -88,6 +98,21 @@ export function getRepositoryFactories(): RepositoryFactories {
     createCategoryRepository: (db) => new TodoCategoryRepository(db),
     createTagValidatorRepository: (db) => new TodoTagValidatorRepository(db),
     createTodoTagRepository: (db) => new TodoTagRepository(db),
+    createTodoLabelRepository: (db) => new TodoLabelRepository(db),
+    createIdempotencyKeyRepository: (db) => new IdempotencyKeyRepository(db),
+  };
+}
+
+/**
+ * 設定から優先度ごとの既定の色を取得する
+ * @returns 優先度ごとの既定の色
+ */
+export function getPriorityColors(): PriorityColors {
+  const config = getConfig();
+  return {
+    low: config.PRIORITY_COLOR_LOW,
+    medium: config.PRIORITY_COLOR_MEDIUM,
+    high: config.PRIORITY_COLOR_HIGH,
   };
 }
 
@@ -108,16 +133,38 @@ export function getTodoService(): TodoService {
     new TodoCategoryRepository(db),
     new TodoTagValidatorRepository(db),
     getRepositoryFactories(),
+    getPriorityColors(),
   );
 }
 
+/**
+ * TodoRepositoryのインスタンスを取得する
+ * @returns TodoRepositoryインスタンス
+ */
+export function getTodoRepository(): TodoRepository {
+  return new TodoRepository(getDb());
+}
+
+/**
+ * IdempotencyKeyRepositoryのインスタンスを取得する
+ * @returns IdempotencyKeyRepositoryインスタンス
+ */
+export function getIdempotencyKeyRepository(): IdempotencyKeyRepository {
+  return new IdempotencyKeyRepository(getDb());
+}
+
 /**
  * TodoSearchServiceのインスタンスを取得する
  * @returns TodoSearchServiceインスタンス
  */
 export function getTodoSearchService(): TodoSearchService {
   const db = getDb();
-  return new TodoSearchService(new TodoSearchRepository(db));
+  return new TodoSearchService(
+    new TodoSearchRepository(db),
+    new TodoTagValidatorRepository(db),
+    getUserRepository(),
+    getPriorityColors(),
+  );
 }
 
 // ============================================
@@ -157,5 +204,25 @@ export function getTagRepository(): TagCrudRepository {
  * @returns TagServiceインスタンス
  */
 export function getTagService(): TagService {
-  return new TagService(getTagRepository());
+  return new TagService(getTagRepository(), getConfig().TAG_DEFAULT_COLOR_ENABLED);
+}
+
+// ============================================
+// Label Feature (CRUD)
+// ============================================
+
+/**
+ * LabelCrudRepositoryのインスタンスを取得する
+ * @returns LabelCrudRepositoryインスタンス
+ */
+export function getLabelRepository(): LabelCrudRepository {
+  return new LabelCrudRepository(getDb());
+}
+
+/**
+ * LabelServiceのインスタンスを取得する
+ * @returns LabelServiceインスタンス
+ */
+export function getLabelService(): LabelService {
+  return new LabelService(getLabelRepository());
 }
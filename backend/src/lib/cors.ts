@@ -0,0 +1,96 @@
+/**
+ * CORS設定
+ * @module lib/cors
+ */
+
+import type { cors } from "hono/cors";
+import { TODO } from "./constants";
+import { REQUEST_ID_HEADER } from "./logger";
+
+/** Hono cors ミドルウェアのオプション型 */
+type CorsOptions = NonNullable<Parameters<typeof cors>[0]>;
+
+/** デフォルトの許可オリジン */
+export const DEFAULT_CORS_ORIGINS = ["http://localhost:3000"];
+
+/**
+ * ワイルドカードサブドメインのオリジン（例: https://*.example.com, http://*.example.com:3000）
+ * 1: スキーム, 2: 親ドメイン（2ラベル以上）, 3: ポート
+ */
+const WILDCARD_SUBDOMAIN_ORIGIN = /^(https?:\/\/)\*\.([a-z0-9-]+(?:\.[a-z0-9-]+)+)(:\d+)?$/i;
+
+/**
+ * ワイルドカードサブドメインのオリジンを正規表現に変換する
+ * 親ドメイン自体は含まず、任意の階層のサブドメインに一致する
+ * @param pattern - ワイルドカードを含むオリジン（例: https://*.example.com）
+ * @returns オリジンにマッチする正規表現
+ */
+function wildcardOriginToRegExp(pattern: string): RegExp {
+  const [, scheme = "", domain = "", port = ""] = pattern.match(WILDCARD_SUBDOMAIN_ORIGIN) ?? [];
+  const escape = (value: string) => value.replace(/[.*+?^${}()|[\]\\]/g, "\\$&");
+  return new RegExp(
+    `^${escape(scheme)}[a-z0-9-]+(?:\\.[a-z0-9-]+)*\\.${escape(domain)}${escape(port)}$`,
+    "i",
+  );
+}
+
+/**
+ * 許可オリジンの設定を検証する
+ * 認証情報付きリクエストを許可する場合、ワイルドカード（*）は任意のオリジンに
+ * Cookie/Authorizationを送信させることになるため拒否する。
+ * サブドメインのワイルドカードは先頭ラベルの `*.` のみ許可する
+ * @param origins - 許可オリジンの配列
+ * @param credentials - 認証情報付きリクエストを許可するか
+ * @throws ワイルドカードと認証情報の許可が併用されている場合、ワイルドカードの形式が不正な場合
+ */
+export function validateCorsOrigins(origins: string[], credentials: boolean): void {
+  if (credentials && origins.includes("*")) {
+    throw new Error("CORS_ORIGINS に * を指定する場合は認証情報付きリクエストを許可できません");
+  }
+  for (const origin of origins) {
+    if (origin !== "*" && origin.includes("*") && !WILDCARD_SUBDOMAIN_ORIGIN.test(origin)) {
+      throw new Error(
+        `CORS_ORIGINS のワイルドカードは https://*.example.com の形式で指定してください: ${origin}`,
+      );
+    }
+  }
+}
+
+/**
+ * Originが許可オリジンに含まれるかを判定する関数を作成する
+ * @param origins - 許可オリジンの配列（ワイルドカードサブドメインを含められる）
+ * @returns Originを受け取り、許可される場合trueを返す関数
+ */
+export function createOriginMatcher(origins: string[]): (origin: string) => boolean {
+  const exact = new Set(origins.filter((origin) => !origin.includes("*")));
+  const wildcards = origins
+    .filter((origin) => WILDCARD_SUBDOMAIN_ORIGIN.test(origin))
+    .map(wildcardOriginToRegExp);
+  return (origin) => exact.has(origin) || wildcards.some((pattern) => pattern.test(origin));
+}
+
+/**
+ * CORSミドルウェアのオプションを作成する
+ * リクエストのOriginが許可リスト（ワイルドカードサブドメインを含む）に一致する場合のみ、
+ * そのOriginを反映する
+ * @param origins - 許可オリジンの配列
+ * @returns corsミドルウェアのオプション
+ * @throws 設定が不正な場合
+ */
+export function createCorsOptions(origins: string[]): CorsOptions {
+  const credentials = true;
+  validateCorsOrigins(origins, credentials);
+
+  const isAllowed = createOriginMatcher(origins);
+  return {
+    origin: (origin) => (isAllowed(origin) ? origin : null),
+    credentials,
+    exposeHeaders: [
+      "Authorization",
+      "ETag",
+      REQUEST_ID_HEADER,
+      TODO.IDEMPOTENT_REPLAYED_HEADER,
+      TODO.TOTAL_COUNT_HEADER,
+    ],
+  };
+}
@@ -0,0 +1,101 @@
+/**
+ * 日付ユーティリティ
+ * @module lib/date
+ */
+
+/**
+ * IANAタイムゾーン名として有効かどうかを判定する
+ * @param timeZone - タイムゾーン名（例: "Asia/Tokyo"）
+ * @returns 有効な場合true
+ */
+export function isValidTimeZone(timeZone: string): boolean {
+  try {
+    new Intl.DateTimeFormat("en-US", { timeZone });
+    return true;
+  } catch {
+    return false;
+  }
+}
+
+/**
+ * 指定タイムゾーンでの今日の日付を取得する
+ * @param timeZone - タイムゾーン名（省略時はサーバーのタイムゾーン）
+ * @param now - 基準日時（デフォルト: 現在日時）
+ * @returns 日付文字列（YYYY-MM-DD）
+ */
+export function todayIn(timeZone?: string, now: Date = new Date()): string {
+  // en-CA ロケールは YYYY-MM-DD 形式で出力する
+  return new Intl.DateTimeFormat("en-CA", {
+    timeZone,
+    year: "numeric",
+    month: "2-digit",
+    day: "2-digit",
+  }).format(now);
+}
+
+/**
+ * 日付に日数を加算する
+ * @param date - 日付文字列（YYYY-MM-DD）
+ * @param days - 加算する日数（負の値で減算）
+ * @returns 日付文字列（YYYY-MM-DD）
+ */
+export function addDays(date: string, days: number): string {
+  const result = new Date(`${date}T00:00:00Z`);
+  result.setUTCDate(result.getUTCDate() + days);
+  return result.toISOString().slice(0, 10);
+}
+
+/**
+ * 日付を含む週の開始日（月曜日）を取得する
+ * @param date - 日付文字列（YYYY-MM-DD）
+ * @returns 週の開始日の日付文字列（YYYY-MM-DD）
+ */
+export function startOfWeek(date: string): string {
+  // getUTCDay は日曜日が0のため、月曜日からの経過日数に変換する
+  const daysSinceMonday = (new Date(`${date}T00:00:00Z`).getUTCDay() + 6) % 7;
+  return addDays(date, -daysSinceMonday);
+}
+
+/**
+ * サーバーのタイムゾーン名を取得する
+ * @returns タイムゾーン名
+ */
+export function serverTimeZone(): string {
+  return Intl.DateTimeFormat().resolvedOptions().timeZone;
+}
+
+/**
+ * 日時を指定タイムゾーンのUTCオフセット付きRFC 3339形式に変換する
+ * @param date - 変換する日時
+ * @param timeZone - タイムゾーン名（例: "Asia/Tokyo"）
+ * @returns RFC 3339形式の文字列（例: 2024-01-01T09:00:00.000+09:00）
+ */
+export function toRfc3339InTimeZone(date: Date, timeZone: string): string {
+  const parts = Object.fromEntries(
+    new Intl.DateTimeFormat("en-US", {
+      timeZone,
+      hourCycle: "h23",
+      year: "numeric",
+      month: "2-digit",
+      day: "2-digit",
+      hour: "2-digit",
+      minute: "2-digit",
+      second: "2-digit",
+    })
+      .formatToParts(date)
+      .map((part) => [part.type, part.value]),
+  );
+  const local =
+    `${parts.year}-${parts.month}-${parts.day}` + `T${parts.hour}:${parts.minute}:${parts.second}`;
+  const milliseconds = date.getUTCMilliseconds();
+
+  // 現地時刻をUTCとみなした値との差分がUTCオフセットになる
+  const offsetMinutes = Math.round(
+    (Date.parse(`${local}Z`) - (date.getTime() - milliseconds)) / 60000,
+  );
+  const sign = offsetMinutes < 0 ? "-" : "+";
+  const hours = String(Math.floor(Math.abs(offsetMinutes) / 60)).padStart(2, "0");
+  const minutes = String(Math.abs(offsetMinutes) % 60).padStart(2, "0");
+
+  return `${local}.${String(milliseconds).padStart(3, "0")}${sign}${hours}:${minutes}`;
+}
@@ -3,10 +3,14 @@ import { HTTPException } from "hono/http-exception";
 /** APIエラーコードの型定義 */
 export type ErrorCode =
   | "VALIDATION_ERROR"
+  | "MALFORMED_JSON"
   | "UNAUTHORIZED"
   | "FORBIDDEN"
   | "NOT_FOUND"
+  | "ROUTE_NOT_FOUND"
+  | "METHOD_NOT_ALLOWED"
   | "CONFLICT"
+  | "PRECONDITION_FAILED"
   | "EDIT_TIME_EXPIRED"
   | "INTERNAL_ERROR";
 
@@ -20,7 +24,32 @@ export interface ApiErrorResponse {
 }
 
 /** APIで使用するHTTPステータスコードの型定義 */
-export type ApiErrorStatusCode = 400 | 401 | 403 | 404 | 409 | 422 | 500;
+export type ApiErrorStatusCode = 400 | 401 | 403 | 404 | 405 | 409 | 412 | 422 | 500;
+
+/**
+ * エラーカタログ
+ * エラーコードとHTTPステータスの対応の唯一の定義元。クライアントとの契約となるため変更時は注意する
+ *
+ * 403と404の使い分け:
+ * - パスで指定したリソースが他ユーザーのもの → 404 NOT_FOUND（存在を明かさない）
+ *   （HIDE_CROSS_USER_AS_404=false の場合は 403 FORBIDDEN）
+ * - リクエストボディの category_id が他ユーザーのカテゴリ → 403 FORBIDDEN
+ * - リクエストボディの tag_ids, label_ids, add_tag_ids, remove_tag_ids に他ユーザーのものや
+ *   存在しないIDが含まれる → 400 VALIDATION_ERROR（使用できないIDを details.<フィールド名> に列挙）
+ */
+export const ERROR_CATALOG = {
+  VALIDATION_ERROR: 400,
+  MALFORMED_JSON: 400,
+  UNAUTHORIZED: 401,
+  FORBIDDEN: 403,
+  EDIT_TIME_EXPIRED: 403,
+  NOT_FOUND: 404,
+  ROUTE_NOT_FOUND: 404,
+  METHOD_NOT_ALLOWED: 405,
+  CONFLICT: 409,
+  PRECONDITION_FAILED: 412,
+  INTERNAL_ERROR: 500,
+} as const satisfies Record<ErrorCode, ApiErrorStatusCode>;
 
 /**
  * API エラークラス
@@ -65,6 +94,21 @@ export class ApiError extends HTTPException {
   }
 }
 
+/**
+ * エラーカタログに従ってApiErrorを作成する
+ * @param code - エラーコード
+ * @param message - エラーメッセージ
+ * @param details - エラーの詳細（オプション）
+ * @returns ApiError
+ */
+export function createApiError(
+  code: ErrorCode,
+  message: string,
+  details?: Record<string, string[]>,
+): ApiError {
+  return new ApiError(ERROR_CATALOG[code], code, message, details);
+}
+
 /**
  * バリデーションエラーを作成する（400）
  * @param message - エラーメッセージ
@@ -72,7 +116,20 @@ export class ApiError extends HTTPException {
  * @returns ApiError
  */
 export function validationError(message: string, details?: Record<string, string[]>): ApiError {
-  return new ApiError(400, "VALIDATION_ERROR", message, details);
+  return createApiError("VALIDATION_ERROR", message, details);
+}
+
+/**
+ * JSON構文エラーを作成する（400）
+ * @param offset - 構文エラーの位置（先頭からのバイト数、判明している場合のみ）
+ * @returns ApiError
+ */
+export function malformedJson(offset?: number): ApiError {
+  return createApiError(
+    "MALFORMED_JSON",
+    "リクエストボディのJSONの形式が不正です",
+    offset === undefined ? undefined : { body: [`${offset}バイト目で構文エラーが発生しました`] },
+  );
 }
 
 /**
@@ -81,16 +138,20 @@ export function validationError(message: string, details?: Record<string, string
  * @returns ApiError
  */
 export function unauthorized(message = "認証が必要です"): ApiError {
-  return new ApiError(401, "UNAUTHORIZED", message);
+  return createApiError("UNAUTHORIZED", message);
 }
 
 /**
  * 権限エラーを作成する（403）
  * @param message - エラーメッセージ（デフォルト: "アクセス権限がありません"）
+ * @param details - 詳細なエラー情報
  * @returns ApiError
  */
-export function forbidden(message = "アクセス権限がありません"): ApiError {
-  return new ApiError(403, "FORBIDDEN", message);
+export function forbidden(
+  message = "アクセス権限がありません",
+  details?: Record<string, string[]>,
+): ApiError {
+  return createApiError("FORBIDDEN", message, details);
 }
 
 /**
@@ -101,16 +162,48 @@ export function forbidden(message = "アクセス権限がありません"): Api
  */
 export function notFound(resource: string, id?: number | string): ApiError {
   const message = id ? `${resource}（ID: ${id}）が見つかりません` : `${resource}が見つかりません`;
-  return new ApiError(404, "NOT_FOUND", message);
+  return createApiError("NOT_FOUND", message);
+}
+
+/**
+ * ルート未定義エラーを作成する（404）
+ * @returns ApiError
+ */
+export function routeNotFound(): ApiError {
+  return createApiError("ROUTE_NOT_FOUND", "指定されたエンドポイントは存在しません");
+}
+
+/**
+ * メソッド不許可エラーを作成する（405）
+ * @param method - リクエストされたHTTPメソッド
+ * @returns ApiError
+ */
+export function methodNotAllowed(method: string): ApiError {
+  return createApiError(
+    "METHOD_NOT_ALLOWED",
+    `${method} メソッドはこのエンドポイントで使用できません`,
+  );
 }
 
 /**
  * 競合エラーを作成する（409）
  * @param message - エラーメッセージ
+ * @param field - 競合したフィールド名（指定時は details にフィールドごとのメッセージを含める）
+ * @returns ApiError
+ */
+export function conflict(message: string, field?: string): ApiError {
+  return createApiError("CONFLICT", message, field ? { [field]: [message] } : undefined);
+}
+
+/**
+ * 事前条件不成立エラーを作成する（412）
+ * @param message - エラーメッセージ（デフォルト: "リソースが他のリクエストで更新されています"）
  * @returns ApiError
  */
-export function conflict(message: string): ApiError {
-  return new ApiError(409, "CONFLICT", message);
+export function preconditionFailed(
+  message = "リソースが他のリクエストで更新されています",
+): ApiError {
+  return createApiError("PRECONDITION_FAILED", message);
 }
 
 /**
@@ -119,7 +212,7 @@ export function conflict(message: string): ApiError {
  * @returns ApiError
  */
 export function editTimeExpired(message = "編集可能時間を過ぎています"): ApiError {
-  return new ApiError(403, "EDIT_TIME_EXPIRED", message);
+  return createApiError("EDIT_TIME_EXPIRED", message);
 }
 
 /**
@@ -128,7 +221,7 @@ export function editTimeExpired(message = "編集可能時間を過ぎていま
  * @returns ApiError
  */
 export function internalError(message = "内部エラーが発生しました"): ApiError {
-  return new ApiError(500, "INTERNAL_ERROR", message);
+  return createApiError("INTERNAL_ERROR", message);
 }
 
 /** joseライブラリのエラー名 */
@@ -0,0 +1,57 @@
+/**
+ * 構造化ロガー
+ * リクエストごとのロガー（リクエストID・ユーザーIDを含む）をコンテキストに保持する
+ * @module lib/logger
+ */
+
+import type { Context } from "hono";
+import { type Logger, pino } from "pino";
+import { isRecord } from "./type-guards";
+
+/** リクエストIDのヘッダー名 */
+export const REQUEST_ID_HEADER = "X-Request-Id";
+
+/** ルートロガー */
+export const rootLogger: Logger = pino();
+
+/** コンテキストにロガーを保持するキー */
+const LOGGER_CONTEXT_KEY = "logger";
+
+/**
+ * Loggerの型ガード
+ * @param value - 検証する値
+ * @returns Loggerかどうか
+ */
+function isLogger(value: unknown): value is Logger {
+  return isRecord(value) && typeof value.child === "function" && typeof value.info === "function";
+}
+
+/**
+ * リクエストに紐づくロガーを取得する
+ * コンテキストに設定されていない場合はルートロガーを返す
+ * @param c - Honoコンテキスト
+ * @returns ロガー
+ */
+export function getLogger(c: Context): Logger {
+  const logger: unknown = c.get(LOGGER_CONTEXT_KEY);
+  return isLogger(logger) ? logger : rootLogger;
+}
+
+/**
+ * リクエストに紐づくロガーを設定する
+ * @param c - Honoコンテキスト
+ * @param logger - ロガー
+ */
+export function setLogger(c: Context, logger: Logger): void {
+  c.set(LOGGER_CONTEXT_KEY, logger);
+}
+
+/**
+ * リクエストに紐づくロガーにフィールドを追加する
+ * 以降の getLogger で取得するロガーのログに含まれる
+ * @param c - Honoコンテキスト
+ * @param bindings - 追加するフィールド
+ */
+export function bindLogger(c: Context, bindings: Record<string, unknown>): void {
+  setLogger(c, getLogger(c).child(bindings));
+}
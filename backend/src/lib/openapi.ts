@@ -0,0 +1,589 @@
+/**
+ * OpenAPIドキュメント生成
+ * エンドポイント定義と、ルートで使用しているZodスキーマからOpenAPI 3.1のドキュメントを組み立てる
+ * @module lib/openapi
+ */
+
+import { z } from "zod";
+import { signInSchema, signUpSchema } from "../features/auth/validators";
+import {
+  createCategorySchema,
+  listCategoryQuerySchema,
+  updateCategorySchema,
+} from "../features/category/validators";
+import { createLabelSchema, updateLabelSchema } from "../features/label/validators";
+import { reminderQuerySchema } from "../features/reminder/validators";
+import { createTagSchema, updateTagSchema } from "../features/tag/validators";
+import {
+  categoryTodoSearchSchema,
+  searchTodoSchema,
+  tagTodoSearchSchema,
+} from "../features/todo/search-validators";
+import {
+  agendaQuerySchema,
+  batchQuerySchema,
+  bulkTagsSchema,
+  createTodoHeaderSchema,
+  createTodoSchema,
+  listTodoQuerySchema,
+  moveTodoSchema,
+  showTodoQuerySchema,
+  snoozeTodoSchema,
+  trendsQuerySchema,
+  updateOrderSchema,
+  updateTodoHeaderSchema,
+  updateTodoSchema,
+} from "../features/todo/validators";
+import { envelopeQuerySchema, idParamSchema } from "../shared/validators/common";
+import {
+  agendaResponseSchema,
+  archiveCompletedTodosResponseSchema,
+  authResponseSchema,
+  bulkTagsResponseSchema,
+  categoryListResponseSchema,
+  categoryResponseSchema,
+  deleteCompletedTodosResponseSchema,
+  errorResponseSchema,
+  labelListResponseSchema,
+  labelResponseSchema,
+  metadataResponseSchema,
+  tagListResponseSchema,
+  tagResponseSchema,
+  todoListResponseSchema,
+  todoResponseSchema,
+  todoTrendsResponseSchema,
+  todoWithNeighborsResponseSchema,
+} from "../shared/validators/responses";
+
+/** OpenAPIドキュメント（JSONとして出力できるオブジェクト） */
+export type OpenApiDocument = Record<string, unknown>;
+
+/** エンドポイント定義 */
+interface EndpointDefinition {
+  method: "get" | "post" | "patch" | "delete";
+  /** Honoのルートパス（例: /api/v1/todos/:id） */
+  path: string;
+  /** 概要 */
+  summary: string;
+  /** 分類タグ */
+  tag: string;
+  /** 認証が必要か（デフォルト: true） */
+  auth?: boolean;
+  /** パスパラメータのスキーマ */
+  params?: z.ZodObject;
+  /** クエリパラメータのスキーマ */
+  query?: z.ZodObject;
+  /** リクエストヘッダーのスキーマ */
+  headers?: z.ZodObject;
+  /** リクエストボディのスキーマ */
+  body?: z.ZodType;
+  /** 成功時のステータスコード */
+  status: 200 | 201 | 204;
+  /** 成功時のレスポンスボディのスキーマ（204の場合は省略） */
+  response?: z.ZodType;
+}
+
+/**
+ * 検索レスポンススキーマ
+ * meta・suggestions は検索条件に応じて内容が変わるため、構造の詳細は定義しない
+ */
+const todoSearchResponseSchema = z.object({
+  data: z.array(todoResponseSchema),
+  meta: z.record(z.string(), z.unknown()),
+  suggestions: z.array(z.record(z.string(), z.unknown())).optional(),
+});
+
+/**
+ * ドキュメントに含めるエンドポイントの一覧
+ * ルートを追加・変更した場合はここも更新する（tests/openapi.test.ts で登録済みルートとの一致を検証する）
+ */
+const ENDPOINTS: EndpointDefinition[] = [
+  // Health
+  {
+    method: "get",
+    path: "/health",
+    summary: "ヘルスチェック",
+    tag: "Health",
+    auth: false,
+    status: 200,
+    response: z.object({ status: z.string(), timestamp: z.string() }),
+  },
+
+  // Auth
+  {
+    method: "post",
+    path: "/auth/sign_up",
+    summary: "ユーザー登録",
+    tag: "Auth",
+    auth: false,
+    body: signUpSchema,
+    status: 201,
+    response: authResponseSchema,
+  },
+  {
+    method: "post",
+    path: "/auth/sign_in",
+    summary: "ログイン",
+    tag: "Auth",
+    auth: false,
+    body: signInSchema,
+    status: 200,
+    response: authResponseSchema,
+  },
+  { method: "delete", path: "/auth/sign_out", summary: "ログアウト", tag: "Auth", status: 204 },
+
+  // Todos
+  {
+    method: "get",
+    path: "/api/v1/todos",
+    summary: "Todo一覧を取得",
+    tag: "Todos",
+    query: listTodoQuerySchema,
+    status: 200,
+    response: todoListResponseSchema,
+  },
+  {
+    method: "get",
+    path: "/api/v1/todos/search",
+    summary: "Todoを検索",
+    tag: "Todos",
+    query: searchTodoSchema,
+    status: 200,
+    response: todoSearchResponseSchema,
+  },
+  {
+    method: "get",
+    path: "/api/v1/todos/agenda",
+    summary: "今日のアジェンダを取得",
+    tag: "Todos",
+    query: agendaQuerySchema,
+    status: 200,
+    response: agendaResponseSchema,
+  },
+  {
+    method: "get",
+    path: "/api/v1/todos/trends",
+    summary: "作成数・完了数の推移を取得",
+    tag: "Todos",
+    query: trendsQuerySchema,
+    status: 200,
+    response: todoTrendsResponseSchema,
+  },
+  {
+    method: "get",
+    path: "/api/v1/todos/batch",
+    summary: "指定したIDのTodoを一括取得",
+    tag: "Todos",
+    query: batchQuerySchema,
+    status: 200,
+    response: todoListResponseSchema,
+  },
+  {
+    method: "get",
+    path: "/api/v1/todos/:id",
+    summary: "Todo詳細を取得",
+    tag: "Todos",
+    params: idParamSchema,
+    query: showTodoQuerySchema,
+    status: 200,
+    response: todoWithNeighborsResponseSchema,
+  },
+  {
+    method: "post",
+    path: "/api/v1/todos",
+    summary: "Todoを作成",
+    tag: "Todos",
+    headers: createTodoHeaderSchema,
+    body: createTodoSchema,
+    status: 201,
+    response: todoResponseSchema,
+  },
+  {
+    method: "patch",
+    path: "/api/v1/todos/update_order",
+    summary: "Todoの並び順を一括更新",
+    tag: "Todos",
+    body: updateOrderSchema,
+    status: 204,
+  },
+  {
+    method: "post",
+    path: "/api/v1/todos/bulk_tags",
+    summary: "複数のTodoのタグを一括で付け外し",
+    tag: "Todos",
+    body: bulkTagsSchema,
+    status: 200,
+    response: bulkTagsResponseSchema,
+  },
+  {
+    method: "patch",
+    path: "/api/v1/todos/:id",
+    summary: "Todoを更新",
+    tag: "Todos",
+    params: idParamSchema,
+    headers: updateTodoHeaderSchema,
+    body: updateTodoSchema,
+    status: 200,
+    response: todoResponseSchema,
+  },
+  {
+    method: "post",
+    path: "/api/v1/todos/:id/move",
+    summary: "Todoを別のTodoの前後に移動",
+    tag: "Todos",
+    params: idParamSchema,
+    body: moveTodoSchema,
+    status: 200,
+    response: todoResponseSchema,
+  },
+  {
+    method: "post",
+    path: "/api/v1/todos/:id/snooze",
+    summary: "Todoをスヌーズ",
+    tag: "Todos",
+    params: idParamSchema,
+    body: snoozeTodoSchema,
+    status: 200,
+    response: todoResponseSchema,
+  },
+  {
+    method: "post",
+    path: "/api/v1/todos/:id/star",
+    summary: "Todoにスターを付ける",
+    tag: "Todos",
+    params: idParamSchema,
+    status: 200,
+    response: todoResponseSchema,
+  },
+  {
+    method: "delete",
+    path: "/api/v1/todos/:id/star",
+    summary: "Todoのスターを外す",
+    tag: "Todos",
+    params: idParamSchema,
+    status: 200,
+    response: todoResponseSchema,
+  },
+  {
+    method: "post",
+    path: "/api/v1/todos/archive_completed",
+    summary: "完了済みのTodoをすべてアーカイブ",
+    tag: "Todos",
+    status: 200,
+    response: archiveCompletedTodosResponseSchema,
+  },
+  {
+    method: "delete",
+    path: "/api/v1/todos/completed",
+    summary: "完了済みのTodoをすべて削除",
+    tag: "Todos",
+    status: 200,
+    response: deleteCompletedTodosResponseSchema,
+  },
+  {
+    method: "delete",
+    path: "/api/v1/todos/:id",
+    summary: "Todoを削除",
+    tag: "Todos",
+    params: idParamSchema,
+    status: 204,
+  },
+
+  // Categories
+  {
+    method: "get",
+    path: "/api/v1/categories",
+    summary: "カテゴリ一覧を取得",
+    tag: "Categories",
+    query: listCategoryQuerySchema,
+    status: 200,
+    response: categoryListResponseSchema,
+  },
+  {
+    method: "get",
+    path: "/api/v1/categories/:id",
+    summary: "カテゴリ詳細を取得",
+    tag: "Categories",
+    params: idParamSchema,
+    status: 200,
+    response: categoryResponseSchema,
+  },
+  {
+    method: "get",
+    path: "/api/v1/categories/:id/todos",
+    summary: "カテゴリのTodo一覧を取得",
+    tag: "Categories",
+    params: idParamSchema,
+    query: categoryTodoSearchSchema,
+    status: 200,
+    response: todoSearchResponseSchema,
+  },
+  {
+    method: "post",
+    path: "/api/v1/categories",
+    summary: "カテゴリを作成",
+    tag: "Categories",
+    body: createCategorySchema,
+    status: 201,
+    response: categoryResponseSchema,
+  },
+  {
+    method: "post",
+    path: "/api/v1/categories/recalculate",
+    summary: "カテゴリのTodo件数を再計算",
+    tag: "Categories",
+    status: 200,
+    response: categoryListResponseSchema,
+  },
+  {
+    method: "patch",
+    path: "/api/v1/categories/:id",
+    summary: "カテゴリを更新",
+    tag: "Categories",
+    params: idParamSchema,
+    body: updateCategorySchema,
+    status: 200,
+    response: categoryResponseSchema,
+  },
+  {
+    method: "delete",
+    path: "/api/v1/categories/:id",
+    summary: "カテゴリを削除",
+    tag: "Categories",
+    params: idParamSchema,
+    status: 204,
+  },
+
+  // Tags
+  {
+    method: "get",
+    path: "/api/v1/tags",
+    summary: "タグ一覧を取得",
+    tag: "Tags",
+    query: envelopeQuerySchema,
+    status: 200,
+    response: tagListResponseSchema,
+  },
+  {
+    method: "get",
+    path: "/api/v1/tags/:id",
+    summary: "タグ詳細を取得",
+    tag: "Tags",
+    params: idParamSchema,
+    status: 200,
+    response: tagResponseSchema,
+  },
+  {
+    method: "get",
+    path: "/api/v1/tags/:id/todos",
+    summary: "タグが付いたTodo一覧を取得",
+    tag: "Tags",
+    params: idParamSchema,
+    query: tagTodoSearchSchema,
+    status: 200,
+    response: todoSearchResponseSchema,
+  },
+  {
+    method: "post",
+    path: "/api/v1/tags",
+    summary: "タグを作成",
+    tag: "Tags",
+    body: createTagSchema,
+    status: 201,
+    response: tagResponseSchema,
+  },
+  {
+    method: "patch",
+    path: "/api/v1/tags/:id",
+    summary: "タグを更新",
+    tag: "Tags",
+    params: idParamSchema,
+    body: updateTagSchema,
+    status: 200,
+    response: tagResponseSchema,
+  },
+  {
+    method: "delete",
+    path: "/api/v1/tags/:id",
+    summary: "タグを削除",
+    tag: "Tags",
+    params: idParamSchema,
+    status: 204,
+  },
+
+  // Labels
+  {
+    method: "get",
+    path: "/api/v1/labels",
+    summary: "ラベル一覧を取得",
+    tag: "Labels",
+    query: envelopeQuerySchema,
+    status: 200,
+    response: labelListResponseSchema,
+  },
+  {
+    method: "get",
+    path: "/api/v1/labels/:id",
+    summary: "ラベル詳細を取得",
+    tag: "Labels",
+    params: idParamSchema,
+    status: 200,
+    response: labelResponseSchema,
+  },
+  {
+    method: "post",
+    path: "/api/v1/labels",
+    summary: "ラベルを作成",
+    tag: "Labels",
+    body: createLabelSchema,
+    status: 201,
+    response: labelResponseSchema,
+  },
+  {
+    method: "patch",
+    path: "/api/v1/labels/:id",
+    summary: "ラベルを更新",
+    tag: "Labels",
+    params: idParamSchema,
+    body: updateLabelSchema,
+    status: 200,
+    response: labelResponseSchema,
+  },
+  {
+    method: "delete",
+    path: "/api/v1/labels/:id",
+    summary: "ラベルを削除",
+    tag: "Labels",
+    params: idParamSchema,
+    status: 204,
+  },
+
+  // Metadata
+  {
+    method: "get",
+    path: "/api/v1/metadata",
+    summary: "カテゴリとタグを一括取得",
+    tag: "Metadata",
+    status: 200,
+    response: metadataResponseSchema,
+  },
+  {
+    method: "get",
+    path: "/api/v1/reminders",
+    summary: "通知時刻が指定期間内に入るTodo一覧を取得",
+    tag: "Reminders",
+    query: reminderQuerySchema,
+    status: 200,
+    response: todoListResponseSchema,
+  },
+];
+
+/**
+ * ZodスキーマをJSON Schemaに変換する
+ * 変換やrefineなどJSON Schemaで表現できない部分は制約なしとして扱う
+ * @param schema - Zodスキーマ
+ * @param io - 入力（リクエスト）と出力（レスポンス）のどちらの型として変換するか
+ * @returns JSON Schema
+ */
+function toJsonSchema(schema: z.ZodType, io: "input" | "output"): Record<string, unknown> {
+  const { $schema: _, ...jsonSchema } = z.toJSONSchema(schema, { io, unrepresentable: "any" });
+  return jsonSchema;
+}
+
+/**
+ * オブジェクトスキーマをOpenAPIのパラメータ定義に変換する
+ * @param schema - パラメータのオブジェクトスキーマ
+ * @param location - パラメータの位置
+ * @returns パラメータ定義の配列
+ */
+function toParameters(
+  schema: z.ZodObject,
+  location: "path" | "query" | "header",
+): Record<string, unknown>[] {
+  const jsonSchema = toJsonSchema(schema, "input");
+  const properties = (jsonSchema.properties ?? {}) as Record<string, unknown>;
+  const required = new Set((jsonSchema.required ?? []) as string[]);
+
+  return Object.entries(properties).map(([name, property]) => ({
+    name,
+    in: location,
+    required: location === "path" || required.has(name),
+    schema: property,
+  }));
+}
+
+/**
+ * エンドポイント定義をOpenAPIのオペレーションに変換する
+ * @param endpoint - エンドポイント定義
+ * @returns オペレーションオブジェクト
+ */
+function toOperation(endpoint: EndpointDefinition): Record<string, unknown> {
+  const parameters = [
+    ...(endpoint.params ? toParameters(endpoint.params, "path") : []),
+    ...(endpoint.query ? toParameters(endpoint.query, "query") : []),
+    ...(endpoint.headers ? toParameters(endpoint.headers, "header") : []),
+  ];
+  const success = endpoint.response
+    ? {
+        description: "成功",
+        content: { "application/json": { schema: toJsonSchema(endpoint.response, "output") } },
+      }
+    : { description: "成功（レスポンスボディなし）" };
+
+  return {
+    summary: endpoint.summary,
+    tags: [endpoint.tag],
+    ...(endpoint.auth !== false && { security: [{ bearerAuth: [] }] }),
+    ...(parameters.length > 0 && { parameters }),
+    ...(endpoint.body && {
+      requestBody: {
+        required: true,
+        content: { "application/json": { schema: toJsonSchema(endpoint.body, "input") } },
+      },
+    }),
+    responses: {
+      [endpoint.status]: success,
+      default: {
+        description: "エラー",
+        content: {
+          "application/json": { schema: { $ref: "#/components/schemas/ErrorResponse" } },
+        },
+      },
+    },
+  };
+}
+
+/**
+ * Honoのルートパスを OpenAPI のパス形式に変換する
+ * @param path - ルートパス（例: /api/v1/todos/:id）
+ * @returns OpenAPIのパス（例: /api/v1/todos/{id}）
+ */
+export function toOpenApiPath(path: string): string {
+  return path.replace(/:(\w+)/g, "{$1}");
+}
+
+/**
+ * OpenAPIドキュメントを作成する
+ * @returns OpenAPI 3.1 ドキュメント
+ */
+export function buildOpenApiDocument(): OpenApiDocument {
+  const paths: Record<string, Record<string, unknown>> = {};
+  for (const endpoint of ENDPOINTS) {
+    const path = toOpenApiPath(endpoint.path);
+    paths[path] = { ...paths[path], [endpoint.method]: toOperation(endpoint) };
+  }
+
+  return {
+    openapi: "3.1.0",
+    info: { title: "Todo API", version: "1.0.0" },
+    paths,
+    components: {
+      securitySchemes: {
+        bearerAuth: { type: "http", scheme: "bearer", bearerFormat: "JWT" },
+      },
+      schemas: {
+        ErrorResponse: toJsonSchema(errorResponseSchema, "output"),
+      },
+    },
+  };
+}
@@ -1,3 +1,4 @@
+import { createHash } from "node:crypto";
 import type { Context } from "hono";
 
 export interface PaginationMeta {
@@ -12,6 +13,15 @@ export interface ListResponse<T> {
   meta: PaginationMeta;
 }
 
+export interface EnvelopeResponse<T> {
+  data: T[];
+  meta: { total: number };
+}
+
+export function envelope<T>(data: T[]): EnvelopeResponse<T> {
+  return { data, meta: { total: data.length } };
+}
+
 export function paginate<T>(
   data: T[],
   total: number,
@@ -40,3 +50,31 @@ export function created<T>(c: Context, data: T) {
 export function noContent(c: Context) {
   return c.body(null, 204);
 }
+
+// レスポンス本文のハッシュから弱いETagを生成する
+// variant には本文以外で表現が変わる要素（タイムスタンプの出力形式など）を渡す
+export function bodyETag(data: unknown, ...variant: string[]): string {
+  const hash = createHash("sha1")
+    .update(JSON.stringify(data))
+    .update(`\n${variant.join("\n")}`)
+    .digest("base64url");
+  return `W/"${hash}"`;
+}
+
+// If-None-Match は弱い比較で判定する（W/ の有無を区別しない）
+function matchesIfNoneMatch(header: string, etag: string): boolean {
+  if (header.trim() === "*") {
+    return true;
+  }
+  const opaque = (tag: string) => tag.trim().replace(/^W\//, "");
+  return header.split(",").some((tag) => opaque(tag) === opaque(etag));
+}
+
+export function okWithETag<T>(c: Context, data: T, etag: string) {
+  c.header("ETag", etag);
+  const ifNoneMatch = c.req.header("If-None-Match");
+  if (ifNoneMatch && matchesIfNoneMatch(ifNoneMatch, etag)) {
+    return c.body(null, 304);
+  }
+  return c.json(data, 200);
+}
@@ -0,0 +1,17 @@
+/**
+ * SQL式ヘルパー
+ * @module lib/sql
+ */
+
+import { type AnyColumn, type SQL, sql } from "drizzle-orm";
+
+/**
+ * 大文字小文字・アクセント記号を区別せずに名前を比較する条件
+ * "Café" と "cafe" を同一とみなす（unaccent拡張が必要）
+ * @param column - 比較対象のカラム
+ * @param name - 比較する名前
+ * @returns SQL条件
+ */
+export function nameMatches(column: AnyColumn, name: string): SQL {
+  return sql`lower(unaccent(${column})) = lower(unaccent(${name}))`;
+}
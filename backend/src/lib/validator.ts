@@ -4,8 +4,11 @@ import { validationError } from "./errors";
  * Zodバリデーションエラーのissue型
  */
 interface ZodIssue {
+  code?: string;
   path: PropertyKey[];
   message: string;
+  /** unrecognized_keys の場合の未知のキー */
+  keys?: string[];
 }
 
 /**
@@ -18,8 +21,12 @@ interface ValidationResult {
   };
 }
 
+/** 未知のフィールドに対するエラーメッセージ */
+const UNKNOWN_FIELD_MESSAGE = "不明なフィールドです";
+
 /**
  * zValidatorのバリデーションエラーハンドラを生成する
+ * 未知のフィールド（strictObjectのunrecognized_keys）はフィールドごとにエラーを設定する
  * @param message - エラー時のメッセージ（デフォルト: "入力内容に誤りがあります"）
  * @returns zValidator用のエラーハンドラ関数
  * @example
@@ -37,12 +44,22 @@ export function handleValidationError(
   return (result) => {
     if (!result.success && result.error) {
       const details: Record<string, string[]> = {};
+      const addDetail = (path: PropertyKey[], detail: string) => {
+        const key = path.map(String).join(".");
+        if (!details[key]) {
+          details[key] = [];
+        }
+        details[key].push(detail);
+      };
+
       for (const issue of result.error.issues) {
-        const path = issue.path.map(String).join(".");
-        if (!details[path]) {
-          details[path] = [];
+        if (issue.code === "unrecognized_keys" && issue.keys) {
+          for (const key of issue.keys) {
+            addDetail([...issue.path, key], UNKNOWN_FIELD_MESSAGE);
+          }
+          continue;
         }
-        details[path].push(issue.message);
+        addDetail(issue.path, issue.message);
       }
       throw validationError(message, details);
     }
@@ -1,4 +1,4 @@
-import { relations } from "drizzle-orm";
+import { relations, sql } from "drizzle-orm";
 import {
   bigint,
   boolean,
@@ -30,6 +30,8 @@ export const users = pgTable(
   },
   (table) => [
     uniqueIndex("users_email_idx").on(table.email),
+    // 大文字小文字だけが異なるメールアドレスの重複を防ぐ
+    uniqueIndex("users_email_lower_idx").on(sql`lower(${table.email})`),
     uniqueIndex("users_reset_password_token_idx").on(table.resetPasswordToken),
   ],
 );
@@ -38,6 +40,7 @@ export const usersRelations = relations(users, ({ many }) => ({
   todos: many(todos),
   categories: many(categories),
   tags: many(tags),
+  labels: many(labels),
   comments: many(comments),
   notes: many(notes),
   todoHistories: many(todoHistories),
@@ -103,6 +106,36 @@ export const tagsRelations = relations(tags, ({ one, many }) => ({
   todoTags: many(todoTags),
 }));
 
+// ============================================
+// Labels
+// ============================================
+export const labels = pgTable(
+  "labels",
+  {
+    id: bigint("id", { mode: "number" }).primaryKey().generatedAlwaysAsIdentity(),
+    userId: bigint("user_id", { mode: "number" })
+      .notNull()
+      .references(() => users.id, { onDelete: "cascade" }),
+    name: varchar("name", { length: 30 }).notNull(),
+    color: varchar("color", { length: 7 }).notNull(),
+    description: varchar("description", { length: 255 }),
+    createdAt: timestamp("created_at").notNull().defaultNow(),
+    updatedAt: timestamp("updated_at").notNull().defaultNow(),
+  },
+  (table) => [
+    index("labels_user_id_idx").on(table.userId),
+    uniqueIndex("labels_user_id_name_idx").on(table.userId, table.name),
+  ],
+);
+
+export const labelsRelations = relations(labels, ({ one, many }) => ({
+  user: one(users, {
+    fields: [labels.userId],
+    references: [users.id],
+  }),
+  todoLabels: many(todoLabels),
+}));
+
 // ============================================
 // Todos
 // ============================================
@@ -123,6 +156,15 @@ export const todos = pgTable(
     priority: integer("priority").notNull().default(1), // 0: low, 1: medium, 2: high
     status: integer("status").notNull().default(0), // 0: pending, 1: in_progress, 2: completed
     dueDate: date("due_date"),
+    reminderOffsetMinutes: integer("reminder_offset_minutes"), // 期限日の何分前に通知するか
+    reminderSentAt: timestamp("reminder_sent_at"), // リマインダーを通知した日時（重複通知の防止用）
+    reminderAttempts: integer("reminder_attempts").notNull().default(0), // 通知に失敗した回数
+    reminderRetryAt: timestamp("reminder_retry_at"), // 通知に失敗した場合の次回の再送日時
+    snoozedUntil: date("snoozed_until"),
+    archivedAt: timestamp("archived_at"),
+    starred: boolean("starred").notNull().default(false),
+    pinned: boolean("pinned").notNull().default(false),
+    completedAt: timestamp("completed_at"),
     createdAt: timestamp("created_at").notNull().defaultNow(),
     updatedAt: timestamp("updated_at").notNull().defaultNow(),
   },
@@ -134,6 +176,10 @@ export const todos = pgTable(
     index("todos_user_id_position_idx").on(table.userId, table.position),
     index("todos_user_id_priority_idx").on(table.userId, table.priority),
     index("todos_user_id_status_idx").on(table.userId, table.status),
+    index("todos_user_id_snoozed_until_idx").on(table.userId, table.snoozedUntil),
+    index("todos_user_id_archived_at_idx").on(table.userId, table.archivedAt),
+    index("todos_user_id_starred_idx").on(table.userId, table.starred),
+    index("todos_user_id_completed_at_idx").on(table.userId, table.completedAt),
     index("todos_title_idx").on(table.title),
     index("todos_due_date_idx").on(table.dueDate),
     index("todos_position_idx").on(table.position),
@@ -154,6 +200,7 @@ export const todosRelations = relations(todos, ({ one, many }) => ({
     references: [categories.id],
   }),
   todoTags: many(todoTags),
+  todoLabels: many(todoLabels),
   comments: many(comments),
   histories: many(todoHistories),
   files: many(files),
@@ -193,6 +240,38 @@ export const todoTagsRelations = relations(todoTags, ({ one }) => ({
   }),
 }));
 
+// ============================================
+// TodoLabels (Junction Table)
+// ============================================
+export const todoLabels = pgTable(
+  "todo_labels",
+  {
+    id: bigint("id", { mode: "number" }).primaryKey().generatedAlwaysAsIdentity(),
+    todoId: bigint("todo_id", { mode: "number" })
+      .notNull()
+      .references(() => todos.id, { onDelete: "cascade" }),
+    labelId: bigint("label_id", { mode: "number" })
+      .notNull()
+      .references(() => labels.id, { onDelete: "cascade" }),
+    createdAt: timestamp("created_at").notNull().defaultNow(),
+  },
+  (table) => [
+    index("todo_labels_label_id_idx").on(table.labelId),
+    uniqueIndex("todo_labels_todo_id_label_id_idx").on(table.todoId, table.labelId),
+  ],
+);
+
+export const todoLabelsRelations = relations(todoLabels, ({ one }) => ({
+  todo: one(todos, {
+    fields: [todoLabels.todoId],
+    references: [todos.id],
+  }),
+  label: one(labels, {
+    fields: [todoLabels.labelId],
+    references: [labels.id],
+  }),
+}));
+
 // ============================================
 // Comments (Polymorphic - currently Todo only)
 // ============================================
@@ -394,6 +473,30 @@ export const filesRelations = relations(files, ({ one }) => ({
   }),
 }));
 
+// ============================================
+// Idempotency Keys
+// ============================================
+export const idempotencyKeys = pgTable(
+  "idempotency_keys",
+  {
+    id: bigint("id", { mode: "number" }).primaryKey().generatedAlwaysAsIdentity(),
+    userId: bigint("user_id", { mode: "number" })
+      .notNull()
+      .references(() => users.id, { onDelete: "cascade" }),
+    key: varchar("key", { length: 255 }).notNull(),
+    todoId: bigint("todo_id", { mode: "number" })
+      .notNull()
+      .references(() => todos.id, { onDelete: "cascade" }),
+    requestHash: varchar("request_hash", { length: 64 }), // リクエスト内容のハッシュ（別内容での再利用の検出用）
+    expiresAt: timestamp("expires_at").notNull(),
+    createdAt: timestamp("created_at").notNull().defaultNow(),
+  },
+  (table) => [
+    uniqueIndex("idempotency_keys_user_id_key_idx").on(table.userId, table.key),
+    index("idempotency_keys_expires_at_idx").on(table.expiresAt),
+  ],
+);
+
 // ============================================
 // Type Exports
 // ============================================
@@ -406,12 +509,18 @@ export type NewCategory = typeof categories.$inferInsert;
 export type Tag = typeof tags.$inferSelect;
 export type NewTag = typeof tags.$inferInsert;
 
+export type Label = typeof labels.$inferSelect;
+export type NewLabel = typeof labels.$inferInsert;
+
 export type Todo = typeof todos.$inferSelect;
 export type NewTodo = typeof todos.$inferInsert;
 
 export type TodoTag = typeof todoTags.$inferSelect;
 export type NewTodoTag = typeof todoTags.$inferInsert;
 
+export type TodoLabel = typeof todoLabels.$inferSelect;
+export type NewTodoLabel = typeof todoLabels.$inferInsert;
+
 export type Comment = typeof comments.$inferSelect;
 export type NewComment = typeof comments.$inferInsert;
 
@@ -429,3 +538,6 @@ export type NewJwtDenylist = typeof jwtDenylists.$inferInsert;
 
 export type File = typeof files.$inferSelect;
 export type NewFile = typeof files.$inferInsert;
+
+export type IdempotencyKey = typeof idempotencyKeys.$inferSelect;
+export type NewIdempotencyKey = typeof idempotencyKeys.$inferInsert;
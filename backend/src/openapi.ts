@@ -0,0 +1,8 @@
+import { writeFileSync } from "node:fs";
+import { buildOpenApiDocument } from "./lib/openapi";
+
+// Usage: pnpm openapi [output]（デフォルト: openapi.json）
+const output = process.argv[2] ?? "openapi.json";
+
+writeFileSync(output, `${JSON.stringify(buildOpenApiDocument(), null, 2)}\n`);
+console.log(`OpenAPI document written to ${output}`);
@@ -9,8 +9,26 @@ export const TODO_ERROR_MESSAGES = {
   CATEGORY_FORBIDDEN: "指定されたカテゴリは使用できません",
   /** タグ使用不可 */
   TAGS_FORBIDDEN: "指定されたタグの一部が使用できません",
+  /** ラベル使用不可 */
+  LABELS_FORBIDDEN: "指定されたラベルの一部が使用できません",
   /** 順序更新不可 */
   ORDER_FORBIDDEN: "更新できないTodoが含まれています",
+  /** 一括更新不可 */
+  BULK_FORBIDDEN: "更新できないTodoが含まれています",
+  /** 移動先の指定なし */
+  MOVE_TARGET_REQUIRED: "after_id または before_id のいずれか一方を指定してください",
+  /** 自身を基準にした移動 */
+  MOVE_SELF: "自身を基準に移動することはできません",
+  /** コメント投稿者に指定したユーザーが存在しない */
+  COMMENTED_BY_NOT_FOUND: "指定されたユーザーが存在しません",
+  /** スヌーズ期限が今日以前 */
+  SNOOZE_UNTIL_NOT_FUTURE: "until には明日以降の日付を指定してください",
+  /** 同じ冪等キーのリクエストを処理中 */
+  IDEMPOTENCY_KEY_IN_USE: "同じ Idempotency-Key のリクエストを処理中です",
+  /** 同じ冪等キーを異なる内容のリクエストで使用 */
+  IDEMPOTENCY_KEY_REUSED: "同じ Idempotency-Key が異なる内容のリクエストで使用されています",
+  /** If-Unmodified-Since 以降に更新済み */
+  MODIFIED_SINCE: "Todoは指定日時より後に更新されています。最新の内容を取得してください",
 } as const;
 
 /** カテゴリ機能のエラーメッセージ */
@@ -27,6 +45,12 @@ export const TAG_ERROR_MESSAGES = {
   DUPLICATE_NAME: "同じ名前のタグが既に存在します",
 } as const;
 
+/** ラベル機能のエラーメッセージ */
+export const LABEL_ERROR_MESSAGES = {
+  /** 名前重複 */
+  DUPLICATE_NAME: "同じ名前のラベルが既に存在します",
+} as const;
+
 /** 認証機能のエラーメッセージ */
 export const AUTH_ERROR_MESSAGES = {
   /** パスワード不一致 */
@@ -3,6 +3,7 @@ import type { TokenPayload } from "../../features/auth/token-schema";
 import { AUTH } from "../../lib/constants";
 import { getAuthService, getUserRepository } from "../../lib/container";
 import { handleJoseError, isJoseError, unauthorized } from "../../lib/errors";
+import { bindLogger } from "../../lib/logger";
 import { hasProperties, isRecord } from "../../lib/type-guards";
 import type { User } from "../../models/schema";
 
@@ -79,6 +80,7 @@ export function jwtAuth(): MiddlewareHandler {
 
       c.set(AUTH.CONTEXT_KEYS.AUTH, { payload, user });
       c.set(AUTH.CONTEXT_KEYS.USER, user);
+      bindLogger(c, { user_id: user.id });
 
       await next();
     } catch (error) {
@@ -0,0 +1,62 @@
+/**
+ * レスポンス圧縮ミドルウェア
+ * @module shared/middleware/compression
+ */
+
+import type { Context, MiddlewareHandler } from "hono";
+import { compress } from "hono/compress";
+
+/** レスポンス圧縮のオプション */
+export interface CompressionOptions {
+  /** 圧縮する最小バイト数（Content-Length がこれ未満の場合は圧縮しない） */
+  threshold: number;
+}
+
+/**
+ * ファイルダウンロードのレスポンスかどうかを判定する
+ * @param c - Honoコンテキスト
+ * @returns Content-Disposition: attachment の場合true
+ */
+function isFileDownload(c: Context): boolean {
+  return /^\s*attachment\b/i.test(c.res.headers.get("Content-Disposition") ?? "");
+}
+
+/**
+ * レスポンスサイズが閾値未満かどうかを判定する
+ * c.json 等のレスポンスには Content-Length が付与されないため、JSONの場合は本文から算出する
+ * @param c - Honoコンテキスト
+ * @param threshold - 圧縮する最小バイト数
+ * @returns 閾値未満の場合true
+ */
+async function isBelowThreshold(c: Context, threshold: number): Promise<boolean> {
+  const contentLength = c.res.headers.get("Content-Length");
+  if (contentLength !== null) {
+    return Number(contentLength) < threshold;
+  }
+  if (!c.res.headers.get("Content-Type")?.startsWith("application/json")) {
+    return false;
+  }
+  const body = await c.res.clone().arrayBuffer();
+  return body.byteLength < threshold;
+}
+
+/**
+ * レスポンス圧縮ミドルウェア
+ * Accept-Encoding に応じて gzip / deflate で圧縮する。
+ * ファイルダウンロード（Content-Disposition: attachment）と、画像・アーカイブ等の
+ * 圧縮済みの Content-Type は二重圧縮を避けるため対象外とする
+ * @param options - レスポンス圧縮のオプション
+ * @returns Honoミドルウェアハンドラー
+ */
+export function compression(options: CompressionOptions): MiddlewareHandler {
+  const compressResponse = compress({ threshold: options.threshold });
+
+  return async (c, next) => {
+    await next();
+    if (isFileDownload(c) || (await isBelowThreshold(c, options.threshold))) {
+      return;
+    }
+    // レスポンスは生成済みのため、compress には何もしない next を渡す
+    await compressResponse(c, async () => {});
+  };
+}
@@ -0,0 +1,54 @@
+/**
+ * JSONリクエストボディ検証ミドルウェア
+ * @module shared/middleware/json-body
+ */
+
+import type { MiddlewareHandler } from "hono";
+import { malformedJson } from "../../lib/errors";
+
+/** JSONとして扱うContent-Type（application/json, application/*+json） */
+const JSON_CONTENT_TYPE = /^application\/(?:[\w.-]+\+)?json\b/i;
+
+/**
+ * JSON.parse の構文エラーから、エラー位置を先頭からのバイト数で取得する
+ * @param error - JSON.parse が投げた例外
+ * @param text - 解析したテキスト
+ * @returns エラー位置のバイト数（判別できない場合はundefined）
+ */
+function syntaxErrorOffset(error: unknown, text: string): number | undefined {
+  if (!(error instanceof SyntaxError)) {
+    return undefined;
+  }
+  const position = error.message.match(/at position (\d+)/);
+  if (position) {
+    return new TextEncoder().encode(text.slice(0, Number(position[1]))).length;
+  }
+  if (error.message.includes("Unexpected end of JSON input")) {
+    return new TextEncoder().encode(text).length;
+  }
+  return undefined;
+}
+
+/**
+ * JSONリクエストボディ検証ミドルウェア
+ * Content-Type が JSON のリクエストボディを事前に解析し、構文エラーを
+ * バリデーションエラーとは区別して 400 MALFORMED_JSON として返す。
+ * 読み込んだ本文は Hono がキャッシュするため、後続の zValidator でも再利用される
+ * @returns Honoミドルウェアハンドラー
+ * @throws リクエストボディがJSONとして解析できない場合
+ */
+export function jsonBody(): MiddlewareHandler {
+  return async (c, next) => {
+    if (JSON_CONTENT_TYPE.test(c.req.header("Content-Type") ?? "")) {
+      const text = await c.req.text();
+      if (text.length > 0) {
+        try {
+          JSON.parse(text);
+        } catch (error) {
+          throw malformedJson(syntaxErrorOffset(error, text));
+        }
+      }
+    }
+    await next();
+  };
+}
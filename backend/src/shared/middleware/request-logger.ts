@@ -0,0 +1,54 @@
+/**
+ * リクエストロガーミドルウェア
+ * @module shared/middleware/request-logger
+ */
+
+import { randomUUID } from "node:crypto";
+import type { MiddlewareHandler } from "hono";
+import type { Logger } from "pino";
+import { getLogger, REQUEST_ID_HEADER, setLogger } from "../../lib/logger";
+
+/** クライアントから受け付けるリクエストIDの形式 */
+const REQUEST_ID_PATTERN = /^[\w-]{1,128}$/;
+
+/** リクエストロガーのオプション */
+export interface RequestLoggerOptions {
+  /** ベースとなるロガー */
+  logger: Logger;
+  /** リクエストの完了をログに出力するか（デフォルト: false） */
+  accessLog?: boolean;
+}
+
+/**
+ * リクエストロガーミドルウェア
+ * リクエストIDを採番（または X-Request-Id ヘッダーを引き継ぎ）し、
+ * リクエストIDを含むロガーをコンテキストに設定する。認証後はユーザーIDも含まれる
+ * @param options - リクエストロガーのオプション
+ * @returns Honoミドルウェアハンドラー
+ */
+export function requestLogger(options: RequestLoggerOptions): MiddlewareHandler {
+  const { logger, accessLog = false } = options;
+
+  return async (c, next) => {
+    const incoming = c.req.header(REQUEST_ID_HEADER);
+    const requestId = incoming && REQUEST_ID_PATTERN.test(incoming) ? incoming : randomUUID();
+
+    setLogger(c, logger.child({ request_id: requestId }));
+    c.header(REQUEST_ID_HEADER, requestId);
+
+    const startedAt = performance.now();
+    await next();
+
+    if (accessLog) {
+      getLogger(c).info(
+        {
+          method: c.req.method,
+          path: c.req.path,
+          status: c.res.status,
+          duration_ms: Math.round(performance.now() - startedAt),
+        },
+        "request completed",
+      );
+    }
+  };
+}
@@ -0,0 +1,115 @@
+/**
+ * タイムスタンプ出力形式ミドルウェア
+ * @module shared/middleware/timestamp-format
+ */
+
+import type { Context, MiddlewareHandler } from "hono";
+import { isValidTimeZone, toRfc3339InTimeZone } from "../../lib/date";
+import { validationError } from "../../lib/errors";
+
+/** タイムスタンプの出力形式（rfc3339: ISO 8601文字列、unix: エポック秒） */
+export type TimestampFormat = "rfc3339" | "unix";
+
+/** タイムスタンプ出力形式のオプション */
+export interface TimestampFormatOptions {
+  /** タイムスタンプの出力形式 */
+  format: TimestampFormat;
+}
+
+/** タイムゾーンを指定するリクエストヘッダー */
+const TIMEZONE_HEADER = "X-Timezone";
+
+/** タイムスタンプの表現（出力形式とタイムゾーン）を保持するコンテキストキー */
+const TIMESTAMP_VARIANT_CONTEXT_KEY = "timestampVariant";
+
+/** タイムゾーンが不正な場合のエラーメッセージ */
+const INVALID_TIMEZONE_MESSAGE = "タイムゾーンはIANA形式（例: Asia/Tokyo）で指定してください";
+
+/**
+ * タイムスタンプを指定された形式に変換する
+ * @param value - ISO 8601形式のタイムスタンプ
+ * @param format - 出力形式
+ * @param timeZone - 出力するタイムゾーン（省略時はUTCのまま）
+ * @returns 変換後の値
+ */
+function formatTimestamp(
+  value: string,
+  format: TimestampFormat,
+  timeZone: string | undefined,
+): string | number {
+  const date = new Date(value);
+  if (format === "unix") {
+    return Math.floor(date.getTime() / 1000);
+  }
+  return timeZone ? toRfc3339InTimeZone(date, timeZone) : value;
+}
+
+/**
+ * JSON内の `*_at` キーのタイムスタンプを再帰的に変換する
+ * @param value - 変換対象の値
+ * @param convert - タイムスタンプの変換関数
+ * @returns 変換後の値
+ */
+function convertTimestamps(value: unknown, convert: (timestamp: string) => unknown): unknown {
+  if (Array.isArray(value)) {
+    return value.map((item) => convertTimestamps(item, convert));
+  }
+  if (value === null || typeof value !== "object") {
+    return value;
+  }
+  return Object.fromEntries(
+    Object.entries(value).map(([key, item]) => {
+      if (key.endsWith("_at") && typeof item === "string" && !Number.isNaN(Date.parse(item))) {
+        return [key, convert(item)];
+      }
+      return [key, convertTimestamps(item, convert)];
+    }),
+  );
+}
+
+/**
+ * リクエストに対するタイムスタンプの表現（出力形式とタイムゾーン）を取得する
+ * 同じデータでも表現によって本文が変わるため、ETagの算出に含める
+ * @param c - Honoコンテキスト
+ * @returns 表現を識別する文字列（ミドルウェア未適用の場合は空文字列）
+ */
+export function getTimestampVariant(c: Context): string {
+  const variant: unknown = c.get(TIMESTAMP_VARIANT_CONTEXT_KEY);
+  return typeof variant === "string" ? variant : "";
+}
+
+/**
+ * タイムスタンプ出力形式ミドルウェア
+ * JSONレスポンスの `*_at` キー（created_at, updated_at 等）を、設定された形式と
+ * X-Timezone ヘッダーで指定されたタイムゾーンに合わせて一括で変換する。
+ * ハンドラーは常にUTCのISO 8601文字列を返し、出力形式はここでのみ決定する
+ * @param options - タイムスタンプ出力形式のオプション
+ * @returns Honoミドルウェアハンドラー
+ * @throws X-Timezone ヘッダーがIANAタイムゾーン名として不正な場合
+ */
+export function timestampFormat(options: TimestampFormatOptions): MiddlewareHandler {
+  return async (c, next) => {
+    const timeZone = c.req.header(TIMEZONE_HEADER);
+    if (timeZone !== undefined && !isValidTimeZone(timeZone)) {
+      throw validationError(INVALID_TIMEZONE_MESSAGE, {
+        [TIMEZONE_HEADER]: [INVALID_TIMEZONE_MESSAGE],
+      });
+    }
+    c.set(TIMESTAMP_VARIANT_CONTEXT_KEY, `${options.format}:${timeZone ?? ""}`);
+
+    await next();
+
+    if (options.format === "rfc3339" && timeZone === undefined) {
+      return;
+    }
+    if (!c.res.headers.get("Content-Type")?.startsWith("application/json")) {
+      return;
+    }
+
+    const body = convertTimestamps(await c.res.json(), (timestamp) =>
+      formatTimestamp(timestamp, options.format, timeZone),
+    );
+    c.res = new Response(JSON.stringify(body), c.res);
+    c.res.headers.delete("Content-Length");
+  };
+}
@@ -1,4 +1,5 @@
 import { z } from "zod";
+import { isValidTimeZone } from "../../lib/date";
 
 /**
  * IDパラメータスキーマ（パスパラメータ用）
@@ -22,22 +23,62 @@ export const idParamSchema = z.object({
 export type IdParam = z.infer<typeof idParamSchema>;
 
 /**
- * HEX色コード正規表現（#RRGGBB形式）
+ * 真偽値クエリパラメータスキーマ
+ * "true" / "false" の文字列を真偽値に変換する
  */
-export const hexColorRegex = /^#[0-9A-Fa-f]{6}$/;
+export const booleanQuerySchema = z
+  .enum(["true", "false"], { message: "true または false を指定してください" })
+  .transform((val) => val === "true");
+
+/**
+ * 一覧エンベロープクエリスキーマ
+ * envelope=true の場合、配列を {data, meta} 形式で返す
+ */
+export const envelopeQuerySchema = z.object({
+  envelope: booleanQuerySchema.optional(),
+});
+
+/**
+ * タイムゾーンスキーマ（IANAタイムゾーン名）
+ */
+export const timeZoneSchema = z.string().refine(isValidTimeZone, {
+  message: "タイムゾーンはIANA形式（例: Asia/Tokyo）で指定してください",
+});
+
+/**
+ * HEX色コード正規表現（#RRGGBB形式、または省略形の #RGB 形式）
+ */
+export const hexColorRegex = /^#(?:[0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$/;
+
+/**
+ * HEX色コードを #RRGGBB 形式に正規化する
+ * #RGB 形式の場合は各桁を2桁に展開する（例: #F00 → #FF0000）
+ * @param color - hexColorRegex に一致する色コード
+ * @returns #RRGGBB 形式の色コード
+ */
+export function normalizeHexColor(color: string): string {
+  if (color.length !== 4) {
+    return color;
+  }
+  return `#${[...color.slice(1)].map((digit) => digit + digit).join("")}`;
+}
 
 /**
  * 必須の色バリデーションスキーマ
+ * #RGB 形式は #RRGGBB 形式に正規化する
  */
 export const requiredColorSchema = z
   .string({ message: "色は必須です" })
-  .regex(hexColorRegex, { message: "色は #RRGGBB 形式で入力してください" });
+  .regex(hexColorRegex, { message: "色は #RRGGBB または #RGB 形式で入力してください" })
+  .transform(normalizeHexColor);
 
 /**
  * オプションの色バリデーションスキーマ
+ * #RGB 形式は #RRGGBB 形式に正規化する
  */
 export const optionalColorSchema = z
   .string()
-  .regex(hexColorRegex, { message: "色は #RRGGBB 形式で入力してください" })
+  .regex(hexColorRegex, { message: "色は #RRGGBB または #RGB 形式で入力してください" })
+  .transform(normalizeHexColor)
   .nullable()
   .optional();
@@ -3,7 +3,8 @@
  * @module shared/validators/ownership
  */
 
-import { forbidden } from "../../lib/errors";
+import { getConfig } from "../../lib/config";
+import { type ApiError, forbidden, notFound, validationError } from "../../lib/errors";
 
 /**
  * IDを持つエンティティのインターフェース
@@ -26,27 +27,67 @@ interface FindByIdRepository<T extends HasId> {
   findById(id: number, userId: number): Promise<T | undefined>;
 }
 
+/**
+ * 所有者を問わないIDによる存在確認が可能なリポジトリのインターフェース
+ */
+interface ExistsByIdRepository {
+  existsById(id: number): Promise<boolean>;
+}
+
+/**
+ * 自分のリソースとして見つからなかった場合のエラーを作成する
+ * 既定では他ユーザーのリソースの存在を明かさないよう常に404とし、
+ * HIDE_CROSS_USER_AS_404=false の場合は他ユーザーのリソースであれば403とする
+ * @param resource - リソース名
+ * @param id - リソースID
+ * @param repository - existsByIdメソッドを持つリポジトリ
+ * @param hideCrossUser - 他ユーザーのリソースも404とするか（デフォルト: 設定値）
+ * @returns NotFoundError または ForbiddenError
+ */
+export async function notFoundOrForbidden(
+  resource: string,
+  id: number,
+  repository: ExistsByIdRepository,
+  hideCrossUser: boolean = getConfig().HIDE_CROSS_USER_AS_404,
+): Promise<ApiError> {
+  if (!hideCrossUser && (await repository.existsById(id))) {
+    return forbidden();
+  }
+  return notFound(resource, id);
+}
+
 /**
  * 複数のエンティティの所有権を検証する
  * @param ids - 検証するエンティティIDの配列
  * @param userId - ユーザーID
  * @param repository - findByIdsメソッドを持つリポジトリ
  * @param errorMessage - エラー時のメッセージ
- * @throws ForbiddenError - 所有権のないエンティティが含まれている場合
+ * @param field - 指定した場合、使用できないIDをこのフィールド名でエラー詳細に含めたバリデーションエラーとする
+ * @throws ForbiddenError - 所有権のないエンティティが含まれている場合（field未指定時）
+ * @throws ValidationError - 所有権のないエンティティが含まれている場合（field指定時）
  */
 export async function validateMultipleOwnership<T extends HasId>(
   ids: number[],
   userId: number,
   repository: FindByIdsRepository<T>,
   errorMessage: string,
+  field?: string,
 ): Promise<void> {
   if (ids.length === 0) {
     return;
   }
   const entities = await repository.findByIds(ids, userId);
-  if (entities.length !== ids.length) {
+  if (entities.length === ids.length) {
+    return;
+  }
+  if (!field) {
     throw forbidden(errorMessage);
   }
+  const ownedIds = new Set(entities.map((e) => e.id));
+  const invalidIds = ids.filter((id) => !ownedIds.has(id));
+  throw validationError(errorMessage, {
+    [field]: invalidIds.map((id) => `ID ${id} は存在しないか、使用できません`),
+  });
 }
 
 /**
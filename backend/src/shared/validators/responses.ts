@@ -30,6 +30,8 @@ export type UserResponse = z.infer<typeof userSchema>;
 export const authResponseSchema = z.object({
   user: userSchema,
   token: z.string(),
+  issued_at: z.string(),
+  expires_at: z.string(),
 });
 
 /** 認証レスポンスの型 */
@@ -49,6 +51,18 @@ export const errorResponseSchema = z.object({
 /** エラーレスポンスの型 */
 export type ErrorResponse = z.infer<typeof errorResponseSchema>;
 
+/**
+ * 一覧エンベロープレスポンススキーマを作成する（envelope=true 指定時）
+ * @param itemSchema - 要素のスキーマ
+ * @returns エンベロープレスポンススキーマ
+ */
+export function envelopeResponseSchema<T extends z.ZodType>(itemSchema: T) {
+  return z.object({
+    data: z.array(itemSchema),
+    meta: z.object({ total: z.number() }),
+  });
+}
+
 // ============================================
 // Category
 // ============================================
@@ -102,6 +116,50 @@ export const tagListResponseSchema = z.array(tagResponseSchema);
 /** タグ一覧レスポンスの型 */
 export type TagListResponse = z.infer<typeof tagListResponseSchema>;
 
+// ============================================
+// Metadata
+// ============================================
+
+/**
+ * メタデータレスポンススキーマ（カテゴリ・タグの一括取得）
+ */
+export const metadataResponseSchema = z.object({
+  categories: categoryListResponseSchema,
+  tags: tagListResponseSchema,
+});
+
+/** メタデータレスポンスの型 */
+export type MetadataResponse = z.infer<typeof metadataResponseSchema>;
+
+// ============================================
+// Label
+// ============================================
+
+/**
+ * ラベルレスポンススキーマ（一覧・詳細用）
+ * todos_count はラベルが付いたTodoの件数
+ */
+export const labelResponseSchema = z.object({
+  id: z.number(),
+  name: z.string(),
+  color: z.string(),
+  description: z.string().nullable(),
+  todos_count: z.number(),
+  created_at: z.string(),
+  updated_at: z.string(),
+});
+
+/** ラベルレスポンスの型 */
+export type LabelResponse = z.infer<typeof labelResponseSchema>;
+
+/**
+ * ラベル一覧レスポンススキーマ
+ */
+export const labelListResponseSchema = z.array(labelResponseSchema);
+
+/** ラベル一覧レスポンスの型 */
+export type LabelListResponse = z.infer<typeof labelListResponseSchema>;
+
 // ============================================
 // Todo
 // ============================================
@@ -130,6 +188,18 @@ export const tagRefSchema = z.object({
 /** タグ参照の型 */
 export type TagRef = z.infer<typeof tagRefSchema>;
 
+/**
+ * ラベル参照スキーマ
+ */
+export const labelRefSchema = z.object({
+  id: z.number(),
+  name: z.string(),
+  color: z.string(),
+});
+
+/** ラベル参照の型 */
+export type LabelRef = z.infer<typeof labelRefSchema>;
+
 /**
  * Todoレスポンススキーマ
  */
@@ -137,13 +207,21 @@ export const todoResponseSchema = z.object({
   id: z.number(),
   title: z.string(),
   completed: z.boolean(),
+  starred: z.boolean(),
+  pinned: z.boolean(),
   position: z.number(),
   due_date: z.string().nullable(),
+  reminder_offset_minutes: z.number().nullable(),
+  snoozed_until: z.string().nullable(),
+  archived_at: z.string().nullable(),
+  completed_at: z.string().nullable(),
   priority: z.enum(["low", "medium", "high"]),
   status: z.enum(["pending", "in_progress", "completed"]),
   description: z.string().nullable(),
   category: categoryRefSchema.nullable(),
+  effective_color: z.string(),
   tags: z.array(tagRefSchema),
+  labels: z.array(labelRefSchema),
   created_at: z.string(),
   updated_at: z.string(),
 });
@@ -151,6 +229,17 @@ export const todoResponseSchema = z.object({
 /** Todoレスポンスの型 */
 export type TodoResponse = z.infer<typeof todoResponseSchema>;
 
+/**
+ * 前後のTodoのID付きTodoレスポンススキーマ（context=position 指定時）
+ */
+export const todoWithNeighborsResponseSchema = todoResponseSchema.extend({
+  prev_id: z.number().nullable(),
+  next_id: z.number().nullable(),
+});
+
+/** 前後のTodoのID付きTodoレスポンスの型 */
+export type TodoWithNeighborsResponse = z.infer<typeof todoWithNeighborsResponseSchema>;
+
 /**
  * Todo一覧レスポンススキーマ
  */
@@ -159,6 +248,67 @@ export const todoListResponseSchema = z.array(todoResponseSchema);
 /** Todo一覧レスポンスの型 */
 export type TodoListResponse = z.infer<typeof todoListResponseSchema>;
 
+/**
+ * アジェンダレスポンススキーマ
+ */
+export const agendaResponseSchema = z.object({
+  date: z.string(),
+  timezone: z.string(),
+  overdue: z.array(todoResponseSchema),
+  today: z.array(todoResponseSchema),
+  snoozed: z.array(todoResponseSchema).optional(),
+});
+
+/** アジェンダレスポンスの型 */
+export type AgendaResponse = z.infer<typeof agendaResponseSchema>;
+
+/**
+ * Todo推移レスポンススキーマ
+ * date は集計単位（日・週）の開始日
+ */
+export const todoTrendsResponseSchema = z.array(
+  z.object({
+    date: z.string(),
+    created: z.number(),
+    completed: z.number(),
+  }),
+);
+
+/** Todo推移レスポンスの型 */
+export type TodoTrendsResponse = z.infer<typeof todoTrendsResponseSchema>;
+
+/**
+ * 完了済みTodo一括削除レスポンススキーマ
+ */
+export const deleteCompletedTodosResponseSchema = z.object({
+  deleted_count: z.number(),
+});
+
+/** 完了済みTodo一括削除レスポンスの型 */
+export type DeleteCompletedTodosResponse = z.infer<typeof deleteCompletedTodosResponseSchema>;
+
+/**
+ * 完了済みTodo一括アーカイブレスポンススキーマ
+ */
+export const archiveCompletedTodosResponseSchema = z.object({
+  archived_count: z.number(),
+});
+
+/** 完了済みTodo一括アーカイブレスポンスの型 */
+export type ArchiveCompletedTodosResponse = z.infer<typeof archiveCompletedTodosResponseSchema>;
+
+/**
+ * タグ一括付け外しレスポンススキーマ
+ */
+export const bulkTagsResponseSchema = z.object({
+  todo_ids: z.array(z.number()),
+  added_count: z.number(),
+  removed_count: z.number(),
+});
+
+/** タグ一括付け外しレスポンスの型 */
+export type BulkTagsResponse = z.infer<typeof bulkTagsResponseSchema>;
+
 // ============================================
 // 後方互換性のためのエイリアス（deprecated）
 // ============================================
@@ -0,0 +1,305 @@
+import { gunzipSync } from "node:zlib";
+import { pino } from "pino";
+import { afterAll, beforeAll, beforeEach, describe, expect, it } from "vitest";
+import { z } from "zod";
+import { createApp } from "../src/lib/app";
+import { errorResponseSchema, todoListResponseSchema } from "../src/shared/validators/responses";
+import { createTestTodo, createTestUser } from "./helpers/factory";
+import { parseResponse } from "./helpers/response";
+import { clearDatabase } from "./setup";
+
+const app = createApp();
+
+describe("アプリケーション共通", () => {
+  let token: string;
+  let userId: number;
+
+  beforeAll(async () => {
+    await clearDatabase();
+  });
+
+  afterAll(async () => {
+    await clearDatabase();
+  });
+
+  beforeEach(async () => {
+    await clearDatabase();
+    const user = await createTestUser();
+    token = user.token;
+    userId = user.userId;
+  });
+
+  describe("未定義ルート", () => {
+    it("異常系: 存在しないパスで404 ROUTE_NOT_FOUND", async () => {
+      const response = await app.request("/api/v1/unknown", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(404);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("ROUTE_NOT_FOUND");
+    });
+
+    it("異常系: 許可されていないメソッドで405 METHOD_NOT_ALLOWED", async () => {
+      const response = await app.request("/api/v1/todos", {
+        method: "PUT",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(405);
+      expect(response.headers.get("Allow")).toContain("GET");
+      expect(response.headers.get("Allow")).toContain("POST");
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("METHOD_NOT_ALLOWED");
+    });
+
+    it("異常系: パラメータ付きパスでもメソッド不一致は405", async () => {
+      const response = await app.request("/api/v1/todos/1", {
+        method: "PUT",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(405);
+      expect(response.headers.get("Allow")).toContain("PATCH");
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("METHOD_NOT_ALLOWED");
+    });
+  });
+
+  describe("CORS", () => {
+    it("正常系: 許可されたオリジンのみAccess-Control-Allow-Originに反映される", async () => {
+      const corsApp = createApp({ corsOrigins: ["https://app.example.com"] });
+
+      const allowed = await corsApp.request("/health", {
+        headers: { Origin: "https://app.example.com" },
+      });
+      expect(allowed.headers.get("Access-Control-Allow-Origin")).toBe("https://app.example.com");
+      expect(allowed.headers.get("Access-Control-Allow-Credentials")).toBe("true");
+
+      const denied = await corsApp.request("/health", {
+        headers: { Origin: "https://evil.example.com" },
+      });
+      expect(denied.headers.get("Access-Control-Allow-Origin")).toBeNull();
+    });
+
+    it("異常系: 認証情報の許可とワイルドカードの併用で起動に失敗する", () => {
+      expect(() => createApp({ corsOrigins: ["*"] })).toThrow();
+    });
+
+    it("正常系: ワイルドカードサブドメインに一致するオリジンのみ許可される", async () => {
+      const corsApp = createApp({ corsOrigins: ["https://*.example.com"] });
+      const allowOrigin = async (origin: string) => {
+        const response = await corsApp.request("/health", { headers: { Origin: origin } });
+        return response.headers.get("Access-Control-Allow-Origin");
+      };
+
+      expect(await allowOrigin("https://staging.example.com")).toBe("https://staging.example.com");
+      expect(await allowOrigin("https://pr-1.preview.example.com")).toBe(
+        "https://pr-1.preview.example.com",
+      );
+      expect(await allowOrigin("https://example.com")).toBeNull();
+      expect(await allowOrigin("http://staging.example.com")).toBeNull();
+      expect(await allowOrigin("https://evil-example.com")).toBeNull();
+      expect(await allowOrigin("https://staging.example.com.evil.com")).toBeNull();
+    });
+
+    it("異常系: 先頭ラベル以外のワイルドカードで起動に失敗する", () => {
+      expect(() => createApp({ corsOrigins: ["https://app.*.com"] })).toThrow();
+      expect(() => createApp({ corsOrigins: ["https://*.com"] })).toThrow();
+      expect(() => createApp({ corsOrigins: ["*.example.com"] })).toThrow();
+    });
+  });
+
+  describe("レスポンス圧縮", () => {
+    const compressedApp = createApp({ enableCompression: true, compressionThreshold: 1024 });
+
+    it("正常系: Accept-Encoding: gzip の場合、閾値以上のJSONをgzipで圧縮する", async () => {
+      for (let i = 0; i < 20; i++) {
+        await createTestTodo({ userId, title: `Todo ${i}`, description: "x".repeat(100) });
+      }
+
+      const response = await compressedApp.request("/api/v1/todos", {
+        headers: { Authorization: `Bearer ${token}`, "Accept-Encoding": "gzip" },
+      });
+
+      expect(response.status).toBe(200);
+      expect(response.headers.get("Content-Encoding")).toBe("gzip");
+      const json = JSON.parse(gunzipSync(Buffer.from(await response.arrayBuffer())).toString());
+      expect(todoListResponseSchema.parse(json)).toHaveLength(20);
+    });
+
+    it("正常系: 閾値未満のレスポンスは圧縮しない", async () => {
+      const response = await compressedApp.request("/health", {
+        headers: { "Accept-Encoding": "gzip" },
+      });
+
+      expect(response.status).toBe(200);
+      expect(response.headers.get("Content-Encoding")).toBeNull();
+    });
+
+    it("正常系: Accept-Encoding がない場合は圧縮しない", async () => {
+      for (let i = 0; i < 20; i++) {
+        await createTestTodo({ userId, title: `Todo ${i}`, description: "x".repeat(100) });
+      }
+
+      const response = await compressedApp.request("/api/v1/todos", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.headers.get("Content-Encoding")).toBeNull();
+      expect(await parseResponse(response, todoListResponseSchema)).toHaveLength(20);
+    });
+
+    it("正常系: ファイルダウンロードは圧縮しない", async () => {
+      const downloadApp = createApp({ enableCompression: true, compressionThreshold: 0 });
+      downloadApp.get("/download", (c) => {
+        c.header("Content-Disposition", 'attachment; filename="report.txt"');
+        return c.text("x".repeat(4096));
+      });
+
+      const response = await downloadApp.request("/download", {
+        headers: { "Accept-Encoding": "gzip" },
+      });
+
+      expect(response.headers.get("Content-Encoding")).toBeNull();
+      expect(await response.text()).toBe("x".repeat(4096));
+    });
+  });
+
+  describe("タイムスタンプ形式", () => {
+    const createdAt = new Date("2024-01-01T00:00:00.123Z");
+    const updatedAt = new Date("2024-01-02T15:30:00.000Z");
+
+    it("正常系: unix 形式ではタイムスタンプをエポック秒で返す", async () => {
+      const unixApp = createApp({ timestampFormat: "unix" });
+      const todoId = await createTestTodo({
+        userId,
+        title: "Todo",
+        dueDate: "2024-01-10",
+        createdAt,
+        updatedAt,
+      });
+
+      const response = await unixApp.request(`/api/v1/todos/${todoId}`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(
+        response,
+        z.object({ created_at: z.number(), updated_at: z.number(), due_date: z.string() }),
+      );
+      expect(body.created_at).toBe(1704067200);
+      expect(body.updated_at).toBe(1704209400);
+      expect(body.due_date).toBe("2024-01-10");
+    });
+
+    it("正常系: unix 形式は一覧レスポンスの各要素にも適用される", async () => {
+      const unixApp = createApp({ timestampFormat: "unix" });
+      await createTestTodo({ userId, title: "Todo", createdAt, updatedAt });
+
+      const response = await unixApp.request("/api/v1/todos", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      const body = await parseResponse(response, z.array(z.object({ created_at: z.number() })));
+      expect(body[0]?.created_at).toBe(1704067200);
+    });
+
+    it("正常系: X-Timezone を指定するとそのタイムゾーンのオフセット付きで返す", async () => {
+      const todoId = await createTestTodo({ userId, title: "Todo", createdAt, updatedAt });
+
+      const response = await app.request(`/api/v1/todos/${todoId}`, {
+        headers: { Authorization: `Bearer ${token}`, "X-Timezone": "Asia/Tokyo" },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(
+        response,
+        z.object({ created_at: z.string(), updated_at: z.string() }),
+      );
+      expect(body.created_at).toBe("2024-01-01T09:00:00.123+09:00");
+      expect(body.updated_at).toBe("2024-01-03T00:30:00.000+09:00");
+    });
+
+    it("正常系: X-Timezone がない場合はUTCのまま返す", async () => {
+      const todoId = await createTestTodo({ userId, title: "Todo", createdAt, updatedAt });
+
+      const response = await app.request(`/api/v1/todos/${todoId}`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      const body = await parseResponse(response, z.object({ created_at: z.string() }));
+      expect(body.created_at).toBe("2024-01-01T00:00:00.123Z");
+    });
+
+    it("異常系: 不正な X-Timezone で400", async () => {
+      const response = await app.request("/api/v1/todos", {
+        headers: { Authorization: `Bearer ${token}`, "X-Timezone": "Invalid/Zone" },
+      });
+
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("VALIDATION_ERROR");
+      expect(body.error.details?.["X-Timezone"]).toBeDefined();
+    });
+  });
+
+  describe("リクエストログ", () => {
+    /**
+     * 出力されたログを収集するアプリケーションを作成する
+     * @returns アプリケーションと収集したログ
+     */
+    const createLoggedApp = () => {
+      const lines: Record<string, unknown>[] = [];
+      const logger = pino({}, { write: (line: string) => lines.push(JSON.parse(line)) });
+      return { loggedApp: createApp({ enableLogger: true, logger }), lines };
+    };
+
+    it("正常系: レスポンスにリクエストIDが付与される", async () => {
+      const response = await app.request("/health");
+
+      expect(response.headers.get("X-Request-Id")).toMatch(/^[0-9a-f-]{36}$/);
+    });
+
+    it("正常系: 受け取ったリクエストIDを引き継ぎ、不正な形式は採番し直す", async () => {
+      const inherited = await app.request("/health", {
+        headers: { "X-Request-Id": "client-req-123" },
+      });
+      expect(inherited.headers.get("X-Request-Id")).toBe("client-req-123");
+
+      const replaced = await app.request("/health", {
+        headers: { "X-Request-Id": "bad id; with spaces" },
+      });
+      expect(replaced.headers.get("X-Request-Id")).not.toBe("bad id; with spaces");
+    });
+
+    it("正常系: 認証済みリクエストのログにユーザーIDとリクエストIDが含まれる", async () => {
+      const { loggedApp, lines } = createLoggedApp();
+
+      await loggedApp.request("/api/v1/todos", {
+        headers: { Authorization: `Bearer ${token}`, "X-Request-Id": "req-1" },
+      });
+
+      const entry = lines.find((line) => line.msg === "request completed");
+      expect(entry).toMatchObject({
+        request_id: "req-1",
+        user_id: userId,
+        method: "GET",
+        path: "/api/v1/todos",
+        status: 200,
+      });
+    });
+
+    it("正常系: 未認証リクエストのログにはユーザーIDが含まれない", async () => {
+      const { loggedApp, lines } = createLoggedApp();
+
+      await loggedApp.request("/api/v1/todos");
+
+      const entry = lines.find((line) => line.msg === "request completed");
+      expect(entry).toMatchObject({ status: 401 });
+      expect(entry).not.toHaveProperty("user_id");
+    });
+  });
+});
@@ -1,5 +1,8 @@
+import { decodeJwt } from "jose";
 import { afterAll, beforeAll, beforeEach, describe, expect, it } from "vitest";
+import { checkPasswordPolicy } from "../src/features/auth/password-policy";
 import { createApp } from "../src/lib/app";
+import { getJwtDenylistRepository } from "../src/lib/container";
 import {
   authResponseSchema,
   errorResponseSchema,
@@ -42,6 +45,9 @@ describe("認証API", () => {
       expect(body.user.name).toBe("テストユーザー");
       expect(body.token).toBeDefined();
       expect(typeof body.token).toBe("string");
+      // 有効期間は24時間
+      const lifetime = Date.parse(body.expires_at) - Date.parse(body.issued_at);
+      expect(lifetime).toBe(24 * 60 * 60 * 1000);
     });
 
     it("正常系: 名前なしで登録できる", async () => {
@@ -82,6 +88,49 @@ describe("認証API", () => {
         }),
       });
 
+      expect(response.status).toBe(409);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("CONFLICT");
+      expect(body.error.details?.email).toEqual([body.error.message]);
+    });
+
+    it("正常系: メールアドレスは前後の空白を除き小文字で保存する", async () => {
+      const response = await app.request("/auth/sign_up", {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify({
+          email: "  Mixed.Case@Example.com ",
+          password: "password123",
+          password_confirmation: "password123",
+        }),
+      });
+
+      expect(response.status).toBe(201);
+      const body = await parseResponse(response, authResponseSchema);
+      expect(body.user.email).toBe("mixed.case@example.com");
+    });
+
+    it("異常系: 大文字小文字だけが異なるメールアドレスは重複として409エラー", async () => {
+      await app.request("/auth/sign_up", {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify({
+          email: "Test@x.com",
+          password: "password123",
+          password_confirmation: "password123",
+        }),
+      });
+
+      const response = await app.request("/auth/sign_up", {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify({
+          email: "test@x.com",
+          password: "password123",
+          password_confirmation: "password123",
+        }),
+      });
+
       expect(response.status).toBe(409);
       const body = await parseResponse(response, errorResponseSchema);
       expect(body.error.code).toBe("CONFLICT");
@@ -117,6 +166,7 @@ describe("認証API", () => {
       expect(response.status).toBe(400);
       const body = await parseResponse(response, errorResponseSchema);
       expect(body.error.code).toBe("VALIDATION_ERROR");
+      expect(body.error.details?.password).toEqual(["パスワードは8文字以上で入力してください"]);
     });
 
     it("異常系: 無効なメールアドレスで400エラー", async () => {
@@ -167,6 +217,38 @@ describe("認証API", () => {
       expect(body.token).toBeDefined();
     });
 
+    it("正常系: メールアドレスの大文字小文字を区別せずにログインできる", async () => {
+      const response = await app.request("/auth/sign_in", {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify({
+          email: "Login@Example.com",
+          password: "password123",
+        }),
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, authResponseSchema);
+      expect(body.user.email).toBe("login@example.com");
+    });
+
+    it("正常系: issued_at/expires_atがトークンのiat/expと一致する", async () => {
+      const response = await app.request("/auth/sign_in", {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify({
+          email: "login@example.com",
+          password: "password123",
+        }),
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, authResponseSchema);
+      const claims = decodeJwt(body.token);
+      expect(body.issued_at).toBe(new Date((claims.iat ?? 0) * 1000).toISOString());
+      expect(body.expires_at).toBe(new Date((claims.exp ?? 0) * 1000).toISOString());
+    });
+
     it("異常系: 存在しないメールアドレスで401エラー", async () => {
       const response = await app.request("/auth/sign_in", {
         method: "POST",
@@ -256,4 +338,44 @@ describe("認証API", () => {
       expect(response.status).toBe(401);
     });
   });
+
+  describe("JWTデナイリストのクリーンアップ", () => {
+    it("正常系: 有効期限切れのエントリのみ削除する", async () => {
+      const repository = getJwtDenylistRepository();
+      const now = new Date();
+      await repository.add("expired-jti", new Date(now.getTime() - 60 * 1000));
+      await repository.add("active-jti", new Date(now.getTime() + 60 * 60 * 1000));
+
+      const removed = await repository.cleanupExpired(now);
+
+      expect(removed).toBe(1);
+      expect(await repository.exists("expired-jti")).toBe(false);
+      expect(await repository.exists("active-jti")).toBe(true);
+    });
+  });
+
+  describe("パスワードポリシー", () => {
+    it("正常系: デフォルトのポリシーでは文字数のみ検査する", () => {
+      const policy = { minLength: 8, requireMixedClasses: false };
+
+      expect(checkPasswordPolicy("password123", policy)).toEqual([]);
+      expect(checkPasswordPolicy("passwor", policy)).toEqual([
+        "パスワードは8文字以上で入力してください",
+      ]);
+    });
+
+    it("正常系: 文字種の要件を満たさない項目ごとにエラーを返す", () => {
+      const policy = { minLength: 12, requireMixedClasses: true };
+
+      expect(checkPasswordPolicy("password123", policy)).toEqual([
+        "パスワードは12文字以上で入力してください",
+        "パスワードには英大文字を含めてください",
+      ]);
+      expect(checkPasswordPolicy("PASSWORDPASSWORD", policy)).toEqual([
+        "パスワードには英小文字を含めてください",
+        "パスワードには数字を含めてください",
+      ]);
+      expect(checkPasswordPolicy("Password12345", policy)).toEqual([]);
+    });
+  });
 });
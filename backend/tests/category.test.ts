@@ -1,9 +1,15 @@
+import { eq } from "drizzle-orm";
 import { afterAll, beforeAll, beforeEach, describe, expect, it } from "vitest";
+import { z } from "zod";
 import { createApp } from "../src/lib/app";
+import { getDb } from "../src/lib/db";
+import { categories } from "../src/models/schema";
 import {
   categoryListResponseSchema,
   categoryResponseSchema,
+  envelopeResponseSchema,
   errorResponseSchema,
+  todoResponseSchema,
 } from "../src/shared/validators/responses";
 import { createUserAndGetToken } from "./helpers/auth";
 import { parseResponse } from "./helpers/response";
@@ -11,6 +17,17 @@ import { clearDatabase } from "./setup";
 
 const app = createApp();
 
+/** カテゴリ別Todo一覧レスポンスのスキーマ */
+const categoryTodosResponseSchema = z.object({
+  data: z.array(todoResponseSchema),
+  meta: z.object({
+    total: z.number(),
+    current_page: z.number(),
+    total_pages: z.number(),
+    per_page: z.number(),
+  }),
+});
+
 describe("カテゴリAPI", () => {
   let token: string;
 
@@ -66,6 +83,56 @@ describe("カテゴリAPI", () => {
       expect(body).toHaveLength(2);
     });
 
+    it("正常系: envelope=trueで{data, meta}形式で取得できる", async () => {
+      const response = await app.request("/api/v1/categories?envelope=true", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, envelopeResponseSchema(categoryResponseSchema));
+      expect(body).toEqual({ data: [], meta: { total: 0 } });
+    });
+
+    it("正常系: include_uncategorized=true でカテゴリなしのTodo件数を末尾に含める", async () => {
+      const createResponse = await app.request("/api/v1/categories", {
+        method: "POST",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({ name: "仕事", color: "#FF0000" }),
+      });
+      const category = await parseResponse(createResponse, categoryResponseSchema);
+      for (const body of [
+        { title: "Categorized", category_id: category.id },
+        { title: "Uncategorized 1" },
+        { title: "Uncategorized 2" },
+      ]) {
+        await app.request("/api/v1/todos", {
+          method: "POST",
+          headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+          body: JSON.stringify(body),
+        });
+      }
+
+      const response = await app.request("/api/v1/categories?include_uncategorized=true", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, categoryListResponseSchema);
+      expect(body.map((c) => [c.id, c.name, c.todos_count])).toEqual([
+        [category.id, "仕事", 1],
+        [-1, "uncategorized", 2],
+      ]);
+    });
+
+    it("正常系: include_uncategorized を省略した場合は疑似カテゴリを含めない", async () => {
+      const response = await app.request("/api/v1/categories", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      const body = await parseResponse(response, categoryListResponseSchema);
+      expect(body.some((c) => c.id === -1)).toBe(false);
+    });
+
     it("異常系: 認証なしで401エラー", async () => {
       const response = await app.request("/api/v1/categories");
 
@@ -110,11 +177,51 @@ describe("カテゴリAPI", () => {
         body: JSON.stringify({ name: "重複", color: "#00FF00" }),
       });
 
+      expect(response.status).toBe(409);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("CONFLICT");
+      expect(body.error.details?.name).toEqual([body.error.message]);
+    });
+
+    it("異常系: アクセント記号・大文字小文字のみ異なる名前で409エラー", async () => {
+      await app.request("/api/v1/categories", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ name: "Café", color: "#FF0000" }),
+      });
+
+      const response = await app.request("/api/v1/categories", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ name: "CAFE", color: "#FF0000" }),
+      });
+
       expect(response.status).toBe(409);
       const body = await parseResponse(response, errorResponseSchema);
       expect(body.error.code).toBe("CONFLICT");
     });
 
+    it("異常系: 未知のフィールドで400エラー", async () => {
+      const response = await app.request("/api/v1/categories", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ name: "typo", colour: "#FF0000" }),
+      });
+
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.details).toHaveProperty("colour");
+    });
+
     it("異常系: 名前が空で400エラー", async () => {
       const response = await app.request("/api/v1/categories", {
         method: "POST",
@@ -145,6 +252,21 @@ describe("カテゴリAPI", () => {
       expect(body.error.code).toBe("VALIDATION_ERROR");
     });
 
+    it("正常系: #RGB 形式の色は #RRGGBB 形式に正規化して保存する", async () => {
+      const response = await app.request("/api/v1/categories", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ name: "省略形", color: "#F00" }),
+      });
+
+      expect(response.status).toBe(201);
+      const body = await parseResponse(response, categoryResponseSchema);
+      expect(body.color).toBe("#FF0000");
+    });
+
     it("異常系: 無効な色形式で400エラー", async () => {
       const response = await app.request("/api/v1/categories", {
         method: "POST",
@@ -194,6 +316,67 @@ describe("カテゴリAPI", () => {
     });
   });
 
+  describe("GET /api/v1/categories/:id/todos - カテゴリ別Todo一覧", () => {
+    it("正常系: カテゴリに属するTodoのみ取得できる", async () => {
+      const categoryResponse = await app.request("/api/v1/categories", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ name: "work" }),
+      });
+      const category = await parseResponse(categoryResponse, categoryResponseSchema);
+
+      for (const [title, categoryId] of [
+        ["Work 1", category.id],
+        ["Uncategorized", null],
+        ["Work 2", category.id],
+      ] as const) {
+        await app.request("/api/v1/todos", {
+          method: "POST",
+          headers: {
+            "Content-Type": "application/json",
+            Authorization: `Bearer ${token}`,
+          },
+          body: JSON.stringify({ title, category_id: categoryId }),
+        });
+      }
+
+      const response = await app.request(`/api/v1/categories/${category.id}/todos?per_page=1`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, categoryTodosResponseSchema);
+      expect(body.data).toHaveLength(1);
+      expect(body.data[0].category?.id).toBe(category.id);
+      expect(body.meta.total).toBe(2);
+      expect(body.meta.total_pages).toBe(2);
+    });
+
+    it("異常系: 他ユーザーのカテゴリで404エラー", async () => {
+      const otherToken = await createUserAndGetToken("another@example.com");
+      const categoryResponse = await app.request("/api/v1/categories", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${otherToken}`,
+        },
+        body: JSON.stringify({ name: "others" }),
+      });
+      const category = await parseResponse(categoryResponse, categoryResponseSchema);
+
+      const response = await app.request(`/api/v1/categories/${category.id}/todos`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(404);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("NOT_FOUND");
+    });
+  });
+
   describe("PATCH /api/v1/categories/:id - カテゴリ更新", () => {
     it("正常系: カテゴリを更新できる", async () => {
       const createResponse = await app.request("/api/v1/categories", {
@@ -221,6 +404,31 @@ describe("カテゴリAPI", () => {
       expect(body.color).toBe("#FFFFFF");
     });
 
+    it("正常系: アクセント記号のみの変更は自分自身と重複扱いにならない", async () => {
+      const createResponse = await app.request("/api/v1/categories", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ name: "Cafe", color: "#AABBCC" }),
+      });
+      const created = await parseResponse(createResponse, categoryResponseSchema);
+
+      const response = await app.request(`/api/v1/categories/${created.id}`, {
+        method: "PATCH",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ name: "Café" }),
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, categoryResponseSchema);
+      expect(body.name).toBe("Café");
+    });
+
     it("正常系: 名前のみ更新できる", async () => {
       const createResponse = await app.request("/api/v1/categories", {
         method: "POST",
@@ -278,6 +486,7 @@ describe("カテゴリAPI", () => {
       expect(response.status).toBe(409);
       const body = await parseResponse(response, errorResponseSchema);
       expect(body.error.code).toBe("CONFLICT");
+      expect(body.error.details?.name).toEqual([body.error.message]);
     });
 
     it("異常系: 存在しないIDで404エラー", async () => {
@@ -294,6 +503,49 @@ describe("カテゴリAPI", () => {
     });
   });
 
+  describe("POST /api/v1/categories/recalculate - Todo件数の再計算", () => {
+    it("正常系: ずれたtodos_countを実際のTodo件数に修正する", async () => {
+      const createResponse = await app.request("/api/v1/categories", {
+        method: "POST",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({ name: "仕事", color: "#FF0000" }),
+      });
+      const category = await parseResponse(createResponse, categoryResponseSchema);
+      for (const title of ["Todo 1", "Todo 2"]) {
+        await app.request("/api/v1/todos", {
+          method: "POST",
+          headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+          body: JSON.stringify({ title, category_id: category.id }),
+        });
+      }
+      await getDb()
+        .update(categories)
+        .set({ todosCount: 10 })
+        .where(eq(categories.id, category.id));
+
+      const response = await app.request("/api/v1/categories/recalculate", {
+        method: "POST",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, categoryListResponseSchema);
+      expect(body.map((c) => [c.id, c.todos_count])).toEqual([[category.id, 2]]);
+
+      const showResponse = await app.request(`/api/v1/categories/${category.id}`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const shown = await parseResponse(showResponse, categoryResponseSchema);
+      expect(shown.todos_count).toBe(2);
+    });
+
+    it("異常系: 認証なしで401エラー", async () => {
+      const response = await app.request("/api/v1/categories/recalculate", { method: "POST" });
+
+      expect(response.status).toBe(401);
+    });
+  });
+
   describe("DELETE /api/v1/categories/:id - カテゴリ削除", () => {
     it("正常系: カテゴリを削除できる", async () => {
       const createResponse = await app.request("/api/v1/categories", {
@@ -0,0 +1,103 @@
+import { describe, expect, it } from "vitest";
+import { createApp } from "../src/lib/app";
+import {
+  type ApiError,
+  conflict,
+  ERROR_CATALOG,
+  editTimeExpired,
+  forbidden,
+  internalError,
+  malformedJson,
+  methodNotAllowed,
+  notFound,
+  preconditionFailed,
+  routeNotFound,
+  unauthorized,
+  validationError,
+} from "../src/lib/errors";
+import { errorResponseSchema } from "../src/shared/validators/responses";
+import { parseResponse } from "./helpers/response";
+
+/** エラーヘルパーと期待するステータス・コードの一覧 */
+const cases: Array<{ name: string; error: () => ApiError; status: number; code: string }> = [
+  {
+    name: "validationError",
+    error: () => validationError("入力が不正です", { title: ["必須です"] }),
+    status: 400,
+    code: "VALIDATION_ERROR",
+  },
+  { name: "malformedJson", error: () => malformedJson(), status: 400, code: "MALFORMED_JSON" },
+  { name: "unauthorized", error: () => unauthorized(), status: 401, code: "UNAUTHORIZED" },
+  { name: "forbidden", error: () => forbidden(), status: 403, code: "FORBIDDEN" },
+  {
+    name: "editTimeExpired",
+    error: () => editTimeExpired(),
+    status: 403,
+    code: "EDIT_TIME_EXPIRED",
+  },
+  { name: "notFound", error: () => notFound("Todo", 1), status: 404, code: "NOT_FOUND" },
+  { name: "routeNotFound", error: () => routeNotFound(), status: 404, code: "ROUTE_NOT_FOUND" },
+  {
+    name: "methodNotAllowed",
+    error: () => methodNotAllowed("PUT"),
+    status: 405,
+    code: "METHOD_NOT_ALLOWED",
+  },
+  { name: "conflict", error: () => conflict("重複しています"), status: 409, code: "CONFLICT" },
+  {
+    name: "preconditionFailed",
+    error: () => preconditionFailed(),
+    status: 412,
+    code: "PRECONDITION_FAILED",
+  },
+  { name: "internalError", error: () => internalError(), status: 500, code: "INTERNAL_ERROR" },
+];
+
+describe("エラーカタログ", () => {
+  it("全てのエラーコードがテスト対象に含まれている", () => {
+    expect(new Set(cases.map((c) => c.code))).toEqual(new Set(Object.keys(ERROR_CATALOG)));
+  });
+
+  for (const { name, error, status, code } of cases) {
+    it(`${name}: カタログ通りのステータスとコードを返す`, async () => {
+      const app = createApp();
+      app.get("/error", () => {
+        throw error();
+      });
+
+      const response = await app.request("/error");
+
+      expect(response.status).toBe(status);
+      expect(ERROR_CATALOG[code as keyof typeof ERROR_CATALOG]).toBe(status);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe(code);
+      expect(body.error.message).not.toBe("");
+    });
+  }
+
+  it("validationError: detailsを含める", async () => {
+    const app = createApp();
+    app.get("/error", () => {
+      throw validationError("入力が不正です", { title: ["必須です"] });
+    });
+
+    const response = await app.request("/error");
+
+    const body = await parseResponse(response, errorResponseSchema);
+    expect(body.error.details).toEqual({ title: ["必須です"] });
+  });
+
+  it("ApiError以外の例外は500 INTERNAL_ERRORとして返す", async () => {
+    const app = createApp();
+    app.get("/error", () => {
+      throw new Error("unexpected");
+    });
+
+    const response = await app.request("/error");
+
+    expect(response.status).toBe(500);
+    const body = await parseResponse(response, errorResponseSchema);
+    expect(body.error.code).toBe("INTERNAL_ERROR");
+    expect(body.error.message).not.toContain("unexpected");
+  });
+});
@@ -6,7 +6,17 @@
 
 import { createApp } from "../../src/lib/app";
 import { getDb } from "../../src/lib/db";
-import { categories, tags, todoTags, todos } from "../../src/models/schema";
+import { ATTACHABLE_TYPES, COMMENTABLE_TYPES } from "../../src/lib/constants";
+import {
+  categories,
+  comments,
+  files,
+  labels,
+  tags,
+  todoLabels,
+  todoTags,
+  todos,
+} from "../../src/models/schema";
 import { authResponseSchema } from "../../src/shared/validators/responses";
 import { parseResponse } from "./response";
 
@@ -90,6 +100,27 @@ export async function createTestTag(
   return record.id;
 }
 
+/**
+ * テスト用ラベルを作成する
+ * @param userId - ユーザーID
+ * @param name - ラベル名
+ * @param color - ラベルの色
+ * @returns 作成されたラベルのID
+ */
+export async function createTestLabel(
+  userId: number,
+  name = "テストラベル",
+  color = "#d73a4a",
+): Promise<number> {
+  const db = getDb();
+  const result = await db.insert(labels).values({ userId, name, color }).returning();
+  const record = result.at(0);
+  if (!record) {
+    throw new Error("Failed to create test label");
+  }
+  return record.id;
+}
+
 /**
  * テスト用Todoを作成する
  * @param data - Todo作成データ
@@ -102,8 +133,16 @@ export async function createTestTodo(data: {
   priority?: number;
   status?: number;
   dueDate?: string;
+  reminderOffsetMinutes?: number;
   categoryId?: number;
   position?: number;
+  snoozedUntil?: string;
+  archivedAt?: Date;
+  starred?: boolean;
+  pinned?: boolean;
+  completedAt?: Date;
+  createdAt?: Date;
+  updatedAt?: Date;
 }): Promise<number> {
   const db = getDb();
   const result = await db
@@ -115,9 +154,17 @@ export async function createTestTodo(data: {
       priority: data.priority ?? 1,
       status: data.status ?? 0,
       dueDate: data.dueDate ?? null,
+      reminderOffsetMinutes: data.reminderOffsetMinutes ?? null,
       categoryId: data.categoryId ?? null,
       position: data.position ?? 0,
       completed: data.status === 2,
+      snoozedUntil: data.snoozedUntil ?? null,
+      archivedAt: data.archivedAt ?? null,
+      starred: data.starred ?? false,
+      pinned: data.pinned ?? false,
+      completedAt: data.completedAt ?? null,
+      createdAt: data.createdAt,
+      updatedAt: data.updatedAt,
     })
     .returning();
   const record = result.at(0);
@@ -136,3 +183,72 @@ export async function attachTagToTodo(todoId: number, tagId: number): Promise<vo
   const db = getDb();
   await db.insert(todoTags).values({ todoId, tagId });
 }
+
+/**
+ * TodoにLabelを紐付ける
+ * @param todoId - TodoのID
+ * @param labelId - ラベルのID
+ */
+export async function attachLabelToTodo(todoId: number, labelId: number): Promise<void> {
+  const db = getDb();
+  await db.insert(todoLabels).values({ todoId, labelId });
+}
+
+/**
+ * Todoに添付ファイルのレコードを作成する
+ * ストレージへのアップロードは行わず、メタデータのみを登録する
+ * @param data - ファイル作成データ
+ * @returns 作成されたファイルのID
+ */
+export async function createTestFile(data: {
+  userId: number;
+  todoId: number;
+  filename: string;
+}): Promise<number> {
+  const db = getDb();
+  const result = await db
+    .insert(files)
+    .values({
+      userId: data.userId,
+      attachableType: ATTACHABLE_TYPES.TODO,
+      attachableId: data.todoId,
+      filename: data.filename,
+      byteSize: 1024,
+      storageKey: `test/${data.todoId}/${data.filename}`,
+    })
+    .returning();
+  const record = result.at(0);
+  if (!record) {
+    throw new Error("Failed to create test file");
+  }
+  return record.id;
+}
+
+/**
+ * Todoにコメントのレコードを作成する
+ * @param data - コメント作成データ
+ * @returns 作成されたコメントのID
+ */
+export async function createTestComment(data: {
+  userId: number;
+  todoId: number;
+  content?: string;
+  deletedAt?: Date;
+}): Promise<number> {
+  const db = getDb();
+  const result = await db
+    .insert(comments)
+    .values({
+      userId: data.userId,
+      commentableType: COMMENTABLE_TYPES.TODO,
+      commentableId: data.todoId,
+      content: data.content ?? "テストコメント",
+      deletedAt: data.deletedAt,
+    })
+    .returning();
+  const record = result.at(0);
+  if (!record) {
+    throw new Error("Failed to create test comment");
+  }
+  return record.id;
+}
@@ -0,0 +1,184 @@
+import { afterAll, beforeAll, beforeEach, describe, expect, it } from "vitest";
+import { createApp } from "../src/lib/app";
+import {
+  envelopeResponseSchema,
+  errorResponseSchema,
+  labelListResponseSchema,
+  labelResponseSchema,
+  todoResponseSchema,
+} from "../src/shared/validators/responses";
+import {
+  attachLabelToTodo,
+  createTestLabel,
+  createTestTodo,
+  createTestUser,
+} from "./helpers/factory";
+import { parseResponse } from "./helpers/response";
+import { clearDatabase } from "./setup";
+
+const app = createApp();
+
+describe("ラベルAPI", () => {
+  let token: string;
+  let userId: number;
+
+  beforeAll(async () => {
+    await clearDatabase();
+  });
+
+  afterAll(async () => {
+    await clearDatabase();
+  });
+
+  beforeEach(async () => {
+    await clearDatabase();
+    const user = await createTestUser("label-test@example.com");
+    token = user.token;
+    userId = user.userId;
+  });
+
+  const request = (path: string, init: { method?: string; body?: unknown } = {}) =>
+    app.request(path, {
+      method: init.method ?? "GET",
+      headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+      body: init.body === undefined ? undefined : JSON.stringify(init.body),
+    });
+
+  describe("GET /api/v1/labels - ラベル一覧取得", () => {
+    it("正常系: 名前順に使用件数付きで取得できる", async () => {
+      const bugId = await createTestLabel(userId, "bug");
+      await createTestLabel(userId, "enhancement", "#a2eeef");
+      const todo1 = await createTestTodo({ userId, title: "Todo 1" });
+      const todo2 = await createTestTodo({ userId, title: "Todo 2" });
+      await attachLabelToTodo(todo1, bugId);
+      await attachLabelToTodo(todo2, bugId);
+
+      const response = await request("/api/v1/labels");
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, labelListResponseSchema);
+      expect(body.map((l) => [l.name, l.todos_count])).toEqual([
+        ["bug", 2],
+        ["enhancement", 0],
+      ]);
+    });
+
+    it("正常系: 他ユーザーのラベルは含まれない", async () => {
+      const otherUser = await createTestUser("label-other@example.com");
+      await createTestLabel(otherUser.userId, "other");
+
+      const response = await request("/api/v1/labels");
+
+      const body = await parseResponse(response, labelListResponseSchema);
+      expect(body).toEqual([]);
+    });
+
+    it("正常系: envelope=true の場合は {data, meta} 形式で返す", async () => {
+      await createTestLabel(userId, "bug");
+
+      const response = await request("/api/v1/labels?envelope=true");
+
+      const body = await parseResponse(response, envelopeResponseSchema(labelResponseSchema));
+      expect(body.meta.total).toBe(1);
+    });
+  });
+
+  describe("POST /api/v1/labels - ラベル作成", () => {
+    it("正常系: 色と説明を指定して作成できる", async () => {
+      const response = await request("/api/v1/labels", {
+        method: "POST",
+        body: { name: "bug", color: "#d73a4a", description: "Something isn't working" },
+      });
+
+      expect(response.status).toBe(201);
+      const body = await parseResponse(response, labelResponseSchema);
+      expect(body).toMatchObject({
+        name: "bug",
+        color: "#d73a4a",
+        description: "Something isn't working",
+        todos_count: 0,
+      });
+    });
+
+    it("異常系: 色がない場合は400エラー", async () => {
+      const response = await request("/api/v1/labels", {
+        method: "POST",
+        body: { name: "bug" },
+      });
+
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.details?.color).toBeDefined();
+    });
+
+    it("異常系: 大文字小文字のみ異なる同名のラベルは409エラー", async () => {
+      await createTestLabel(userId, "bug");
+
+      const response = await request("/api/v1/labels", {
+        method: "POST",
+        body: { name: "BUG", color: "#d73a4a" },
+      });
+
+      expect(response.status).toBe(409);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.details?.name).toBeDefined();
+    });
+
+    it("正常系: 他ユーザーと同名のラベルは作成できる", async () => {
+      const otherUser = await createTestUser("label-other@example.com");
+      await createTestLabel(otherUser.userId, "bug");
+
+      const response = await request("/api/v1/labels", {
+        method: "POST",
+        body: { name: "bug", color: "#d73a4a" },
+      });
+
+      expect(response.status).toBe(201);
+    });
+  });
+
+  describe("PATCH /api/v1/labels/:id - ラベル更新", () => {
+    it("正常系: 説明を更新しても使用件数を返す", async () => {
+      const labelId = await createTestLabel(userId, "bug");
+      const todoId = await createTestTodo({ userId, title: "Todo" });
+      await attachLabelToTodo(todoId, labelId);
+
+      const response = await request(`/api/v1/labels/${labelId}`, {
+        method: "PATCH",
+        body: { description: "Bug reports" },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, labelResponseSchema);
+      expect(body.description).toBe("Bug reports");
+      expect(body.todos_count).toBe(1);
+    });
+
+    it("異常系: 他ユーザーのラベルは404エラー", async () => {
+      const otherUser = await createTestUser("label-other@example.com");
+      const labelId = await createTestLabel(otherUser.userId, "other");
+
+      const response = await request(`/api/v1/labels/${labelId}`, {
+        method: "PATCH",
+        body: { name: "mine" },
+      });
+
+      expect(response.status).toBe(404);
+    });
+  });
+
+  describe("DELETE /api/v1/labels/:id - ラベル削除", () => {
+    it("正常系: 削除するとTodoからも外れる", async () => {
+      const labelId = await createTestLabel(userId, "bug");
+      const todoId = await createTestTodo({ userId, title: "Todo" });
+      await attachLabelToTodo(todoId, labelId);
+
+      const response = await request(`/api/v1/labels/${labelId}`, { method: "DELETE" });
+      expect(response.status).toBe(204);
+
+      const todoResponse = await request(`/api/v1/todos/${todoId}`);
+      const todo = await parseResponse(todoResponse, todoResponseSchema);
+      expect(todo.labels).toEqual([]);
+    });
+  });
+});
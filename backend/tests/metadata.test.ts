@@ -0,0 +1,65 @@
+import { afterAll, beforeAll, beforeEach, describe, expect, it } from "vitest";
+import { createApp } from "../src/lib/app";
+import { metadataResponseSchema } from "../src/shared/validators/responses";
+import { createTestCategory, createTestTag, createTestUser } from "./helpers/factory";
+import { parseResponse } from "./helpers/response";
+import { clearDatabase } from "./setup";
+
+const app = createApp();
+
+describe("メタデータAPI", () => {
+  let token: string;
+  let userId: number;
+
+  beforeAll(async () => {
+    await clearDatabase();
+  });
+
+  afterAll(async () => {
+    await clearDatabase();
+  });
+
+  beforeEach(async () => {
+    await clearDatabase();
+    const user = await createTestUser("metadata-test@example.com");
+    token = user.token;
+    userId = user.userId;
+  });
+
+  describe("GET /api/v1/metadata - メタデータ一括取得", () => {
+    it("正常系: カテゴリとタグを一度に取得できる", async () => {
+      await createTestCategory(userId, "仕事");
+      await createTestCategory(userId, "個人");
+      await createTestTag(userId, "urgent");
+
+      const response = await app.request("/api/v1/metadata", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, metadataResponseSchema);
+      expect(body.categories.map((category) => category.name).sort()).toEqual(["仕事", "個人"]);
+      expect(body.tags.map((tag) => tag.name)).toEqual(["urgent"]);
+    });
+
+    it("正常系: 他ユーザーのカテゴリ・タグは含まれない", async () => {
+      const other = await createTestUser("metadata-other@example.com");
+      await createTestCategory(other.userId, "他人のカテゴリ");
+      await createTestTag(other.userId, "他人のタグ");
+
+      const response = await app.request("/api/v1/metadata", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, metadataResponseSchema);
+      expect(body).toEqual({ categories: [], tags: [] });
+    });
+
+    it("異常系: 認証なしで401", async () => {
+      const response = await app.request("/api/v1/metadata");
+
+      expect(response.status).toBe(401);
+    });
+  });
+});
@@ -0,0 +1,40 @@
+import { describe, expect, it } from "vitest";
+import { createApp } from "../src/lib/app";
+import { buildOpenApiDocument, toOpenApiPath } from "../src/lib/openapi";
+
+/** 生成したドキュメントのうち、テストで参照する部分の型 */
+interface PathsDocument {
+  paths: Record<string, Record<string, Record<string, unknown>>>;
+}
+
+describe("OpenAPIドキュメント", () => {
+  const document = buildOpenApiDocument() as unknown as PathsDocument;
+
+  it("正常系: 登録済みの全ルートがドキュメントに含まれている", () => {
+    const routes = createApp()
+      .routes.filter((route) => route.method !== "ALL")
+      .map((route) => `${route.method} ${toOpenApiPath(route.path)}`);
+    const documented = Object.entries(document.paths).flatMap(([path, operations]) =>
+      Object.keys(operations).map((method) => `${method.toUpperCase()} ${path}`),
+    );
+
+    expect(new Set(documented)).toEqual(new Set(routes));
+  });
+
+  it("正常系: リクエストボディの必須項目とパスパラメータを含む", () => {
+    const create = document.paths["/api/v1/todos"]?.post;
+    expect(create?.requestBody).toMatchObject({
+      content: { "application/json": { schema: { required: ["title"] } } },
+    });
+
+    const show = document.paths["/api/v1/todos/{id}"]?.get;
+    expect(show?.parameters).toContainEqual(
+      expect.objectContaining({ name: "id", in: "path", required: true }),
+    );
+  });
+
+  it("正常系: 認証が必要なエンドポイントのみ security を持つ", () => {
+    expect(document.paths["/api/v1/todos"]?.get).toHaveProperty("security");
+    expect(document.paths["/auth/sign_in"]?.post).not.toHaveProperty("security");
+  });
+});
@@ -0,0 +1,31 @@
+import { describe, expect, it } from "vitest";
+import { notFoundOrForbidden } from "../src/shared/validators/ownership";
+
+/** 存在するIDの集合だけを持つテスト用リポジトリ */
+function createRepository(existingIds: number[]) {
+  return {
+    existsById: async (id: number) => existingIds.includes(id),
+  };
+}
+
+describe("notFoundOrForbidden", () => {
+  const repository = createRepository([1]);
+
+  it("他ユーザーのリソースを隠す設定では存在していても404を返す", async () => {
+    const error = await notFoundOrForbidden("Todo", 1, repository, true);
+    expect(error.status).toBe(404);
+    expect(error.code).toBe("NOT_FOUND");
+  });
+
+  it("他ユーザーのリソースを隠さない設定では存在する場合に403を返す", async () => {
+    const error = await notFoundOrForbidden("Todo", 1, repository, false);
+    expect(error.status).toBe(403);
+    expect(error.code).toBe("FORBIDDEN");
+  });
+
+  it("他ユーザーのリソースを隠さない設定でも存在しない場合は404を返す", async () => {
+    const error = await notFoundOrForbidden("Todo", 999, repository, false);
+    expect(error.status).toBe(404);
+    expect(error.code).toBe("NOT_FOUND");
+  });
+});
@@ -0,0 +1,275 @@
+import { createServer } from "node:http";
+import type { AddressInfo } from "node:net";
+import { eq } from "drizzle-orm";
+import { afterAll, beforeAll, beforeEach, describe, expect, it } from "vitest";
+import { dispatchReminders, reminderRetryAt } from "../src/features/reminder/dispatcher";
+import {
+  type ReminderNotifierInterface,
+  WebhookReminderNotifier,
+} from "../src/features/reminder/notifier";
+import { createApp } from "../src/lib/app";
+import { getTodoRepository } from "../src/lib/container";
+import { addDays, todayIn } from "../src/lib/date";
+import { getDb } from "../src/lib/db";
+import { todos } from "../src/models/schema";
+import { errorResponseSchema, todoListResponseSchema } from "../src/shared/validators/responses";
+import { createTestTodo, createTestUser } from "./helpers/factory";
+import { parseResponse } from "./helpers/response";
+import { clearDatabase } from "./setup";
+
+const app = createApp();
+
+/** 2日後の期限日（UTC） */
+const dueDate = () => addDays(todayIn("UTC"), 2);
+
+/**
+ * 通知時刻が現在から指定分後になるオフセットを求める
+ * @param minutesFromNow - 現在から通知時刻までの分数
+ * @returns 期限日の0時（UTC）からのオフセット（分）
+ */
+function offsetFor(minutesFromNow: number): number {
+  const dueAt = Date.parse(`${dueDate()}T00:00:00Z`);
+  return Math.floor((dueAt - Date.now()) / 60000) - minutesFromNow;
+}
+
+describe("リマインダーAPI", () => {
+  let token: string;
+  let userId: number;
+
+  beforeAll(async () => {
+    await clearDatabase();
+  });
+
+  afterAll(async () => {
+    await clearDatabase();
+  });
+
+  beforeEach(async () => {
+    await clearDatabase();
+    const user = await createTestUser("reminder-test@example.com");
+    token = user.token;
+    userId = user.userId;
+  });
+
+  describe("GET /api/v1/reminders - 通知対象のTodo一覧", () => {
+    it("正常系: 通知時刻が指定期間内に入るTodoを通知時刻順に返す", async () => {
+      const soonOffset = offsetFor(10);
+      await createTestTodo({
+        userId,
+        title: "Later",
+        dueDate: dueDate(),
+        reminderOffsetMinutes: offsetFor(50),
+      });
+      await createTestTodo({
+        userId,
+        title: "Soon",
+        dueDate: dueDate(),
+        reminderOffsetMinutes: soonOffset,
+      });
+      await createTestTodo({
+        userId,
+        title: "Out of window",
+        dueDate: dueDate(),
+        reminderOffsetMinutes: offsetFor(120),
+      });
+      await createTestTodo({
+        userId,
+        title: "Passed",
+        dueDate: dueDate(),
+        reminderOffsetMinutes: offsetFor(-30),
+      });
+      await createTestTodo({ userId, title: "No reminder", dueDate: dueDate() });
+
+      const response = await app.request("/api/v1/reminders?window=60&tz=UTC", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoListResponseSchema);
+      expect(body.map((t) => t.title)).toEqual(["Soon", "Later"]);
+      expect(body[0].reminder_offset_minutes).toBe(soonOffset);
+    });
+
+    it("正常系: window を広げると対象が増える", async () => {
+      await createTestTodo({
+        userId,
+        title: "In two hours",
+        dueDate: dueDate(),
+        reminderOffsetMinutes: offsetFor(120),
+      });
+
+      const narrow = await app.request("/api/v1/reminders?tz=UTC", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      expect(await parseResponse(narrow, todoListResponseSchema)).toEqual([]);
+
+      const wide = await app.request("/api/v1/reminders?window=180&tz=UTC", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const body = await parseResponse(wide, todoListResponseSchema);
+      expect(body.map((t) => t.title)).toEqual(["In two hours"]);
+    });
+
+    it("正常系: 完了済み・アーカイブ済み・他ユーザーのTodoは含まない", async () => {
+      const other = await createTestUser("reminder-other@example.com");
+      const reminder = { dueDate: dueDate(), reminderOffsetMinutes: offsetFor(10) };
+      await createTestTodo({ userId, title: "Completed", status: 2, ...reminder });
+      await createTestTodo({ userId, title: "Archived", archivedAt: new Date(), ...reminder });
+      await createTestTodo({ userId: other.userId, title: "Other", ...reminder });
+
+      const response = await app.request("/api/v1/reminders?tz=UTC", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(await parseResponse(response, todoListResponseSchema)).toEqual([]);
+    });
+
+    it("異常系: window が範囲外の場合は400を返す", async () => {
+      const response = await app.request("/api/v1/reminders?window=0", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.details?.window).toBeDefined();
+    });
+
+    it("異常系: 認証なしの場合は401を返す", async () => {
+      const response = await app.request("/api/v1/reminders");
+      expect(response.status).toBe(401);
+    });
+  });
+
+  describe("リマインダーの通知", () => {
+    /** 通知したTodoのIDを記録する通知先 */
+    function createNotifier(): ReminderNotifierInterface & { sentIds: number[] } {
+      const sentIds: number[] = [];
+      return {
+        sentIds,
+        notify: async (todo) => {
+          sentIds.push(todo.id);
+        },
+      };
+    }
+
+    it("正常系: 通知時刻を過ぎたTodoを1回だけ通知する", async () => {
+      const due = await createTestTodo({
+        userId,
+        title: "Due",
+        dueDate: dueDate(),
+        reminderOffsetMinutes: offsetFor(-10),
+      });
+      await createTestTodo({
+        userId,
+        title: "Not yet",
+        dueDate: dueDate(),
+        reminderOffsetMinutes: offsetFor(10),
+      });
+      await createTestTodo({
+        userId,
+        title: "Overdue",
+        dueDate: addDays(todayIn("UTC"), -1),
+        reminderOffsetMinutes: 0,
+      });
+      const notifier = createNotifier();
+
+      expect(await dispatchReminders(getTodoRepository(), notifier, "UTC")).toBe(1);
+      expect(await dispatchReminders(getTodoRepository(), notifier, "UTC")).toBe(0);
+      expect(notifier.sentIds).toEqual([due]);
+    });
+
+    it("正常系: 同時に実行しても同じTodoを重複して通知しない", async () => {
+      const due = await createTestTodo({
+        userId,
+        title: "Due",
+        dueDate: dueDate(),
+        reminderOffsetMinutes: offsetFor(-10),
+      });
+      const notifier = createNotifier();
+
+      const results = await Promise.all([
+        dispatchReminders(getTodoRepository(), notifier, "UTC"),
+        dispatchReminders(getTodoRepository(), notifier, "UTC"),
+      ]);
+
+      expect(results[0] + results[1]).toBe(1);
+      expect(notifier.sentIds).toEqual([due]);
+    });
+
+    it("正常系: 通知に失敗したTodoは間隔を空けて再送する", async () => {
+      const due = await createTestTodo({
+        userId,
+        title: "Due",
+        dueDate: dueDate(),
+        reminderOffsetMinutes: offsetFor(-10),
+      });
+      const failing: ReminderNotifierInterface = {
+        notify: async () => {
+          throw new Error("unavailable");
+        },
+      };
+      const notifier = createNotifier();
+      const now = new Date();
+
+      expect(await dispatchReminders(getTodoRepository(), failing, "UTC", now)).toBe(0);
+      // 再送日時までは対象外
+      expect(await dispatchReminders(getTodoRepository(), notifier, "UTC", now)).toBe(0);
+      const retryAt = reminderRetryAt(1, now) ?? now;
+      expect(await dispatchReminders(getTodoRepository(), notifier, "UTC", retryAt)).toBe(1);
+      expect(notifier.sentIds).toEqual([due]);
+    });
+
+    it("正常系: 再送間隔は失敗するたびに倍になり、最大回数に達すると再送しない", () => {
+      const now = new Date("2025-01-01T00:00:00Z");
+
+      expect(reminderRetryAt(1, now)?.toISOString()).toBe("2025-01-01T00:01:00.000Z");
+      expect(reminderRetryAt(4, now)?.toISOString()).toBe("2025-01-01T00:08:00.000Z");
+      expect(reminderRetryAt(5, now)).toBeNull();
+    });
+
+    it("正常系: オフセットを変更すると再度通知する", async () => {
+      const due = await createTestTodo({
+        userId,
+        title: "Due",
+        dueDate: dueDate(),
+        reminderOffsetMinutes: offsetFor(-10),
+      });
+      const notifier = createNotifier();
+      await dispatchReminders(getTodoRepository(), notifier, "UTC");
+
+      await app.request(`/api/v1/todos/${due}`, {
+        method: "PATCH",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({ reminder_offset_minutes: offsetFor(-5) }),
+      });
+
+      expect(await dispatchReminders(getTodoRepository(), notifier, "UTC")).toBe(1);
+      expect(notifier.sentIds).toEqual([due, due]);
+    });
+
+    it("異常系: 応答しないWebhookはタイムアウトで失敗する", async () => {
+      const server = createServer(() => {
+        // 応答を返さない
+      });
+      await new Promise<void>((resolve) => server.listen(0, "127.0.0.1", resolve));
+      const { port } = server.address() as AddressInfo;
+      const due = await createTestTodo({
+        userId,
+        title: "Due",
+        dueDate: dueDate(),
+        reminderOffsetMinutes: offsetFor(-10),
+      });
+      const notifier = new WebhookReminderNotifier(`http://127.0.0.1:${port}/hooks`, 100);
+
+      try {
+        expect(await dispatchReminders(getTodoRepository(), notifier, "UTC")).toBe(0);
+      } finally {
+        server.closeAllConnections();
+        server.close();
+      }
+      const [todo] = await getDb().select().from(todos).where(eq(todos.id, due));
+      expect(todo?.reminderSentAt).toBeNull();
+      expect(todo?.reminderAttempts).toBe(1);
+    });
+  });
+});
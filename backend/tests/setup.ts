@@ -2,8 +2,13 @@ import { sql } from "drizzle-orm";
 import { getDb } from "../src/lib/db";
 import {
   categories,
+  comments,
+  files,
+  idempotencyKeys,
   jwtDenylists,
+  labels,
   tags,
+  todoLabels,
   todoTags,
   todos,
   users,
@@ -12,10 +17,15 @@ import {
 export async function clearDatabase() {
   const db = getDb();
   // 外部キー制約を考慮して削除順序を設定
+  await db.delete(files);
+  await db.delete(comments);
+  await db.delete(idempotencyKeys);
   await db.delete(todoTags);
+  await db.delete(todoLabels);
   await db.delete(todos);
   await db.delete(categories);
   await db.delete(tags);
+  await db.delete(labels);
   await db.delete(jwtDenylists);
   await db.delete(users);
 }
@@ -1,9 +1,14 @@
 import { afterAll, beforeAll, beforeEach, describe, expect, it } from "vitest";
+import { z } from "zod";
+import { defaultTagColor } from "../src/features/tag/service";
 import { createApp } from "../src/lib/app";
+import { TAG } from "../src/lib/constants";
 import {
+  envelopeResponseSchema,
   errorResponseSchema,
   tagListResponseSchema,
   tagResponseSchema,
+  todoResponseSchema,
 } from "../src/shared/validators/responses";
 import { createUserAndGetToken } from "./helpers/auth";
 import { parseResponse } from "./helpers/response";
@@ -11,6 +16,17 @@ import { clearDatabase } from "./setup";
 
 const app = createApp();
 
+/** タグ別Todo一覧レスポンスのスキーマ */
+const tagTodosResponseSchema = z.object({
+  data: z.array(todoResponseSchema),
+  meta: z.object({
+    total: z.number(),
+    current_page: z.number(),
+    total_pages: z.number(),
+    per_page: z.number(),
+  }),
+});
+
 describe("タグAPI", () => {
   let token: string;
 
@@ -65,6 +81,36 @@ describe("タグAPI", () => {
       expect(body).toHaveLength(2);
     });
 
+    it("正常系: envelope=trueで{data, meta}形式で取得できる", async () => {
+      await app.request("/api/v1/tags", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ name: "urgent" }),
+      });
+
+      const response = await app.request("/api/v1/tags?envelope=true", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, envelopeResponseSchema(tagResponseSchema));
+      expect(body.data.map((t) => t.name)).toEqual(["urgent"]);
+      expect(body.meta.total).toBe(1);
+    });
+
+    it("異常系: envelopeに不正な値を指定すると400エラー", async () => {
+      const response = await app.request("/api/v1/tags?envelope=yes", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("VALIDATION_ERROR");
+    });
+
     it("異常系: 認証なしで401エラー", async () => {
       const response = await app.request("/api/v1/tags");
 
@@ -89,7 +135,7 @@ describe("タグAPI", () => {
       expect(body.color).toBe("#FF5733");
     });
 
-    it("正常系: タグを作成できる（色なし）", async () => {
+    it("正常系: 色を省略すると名前から決まるデフォルト色が割り当てられる", async () => {
       const response = await app.request("/api/v1/tags", {
         method: "POST",
         headers: {
@@ -102,6 +148,22 @@ describe("タグAPI", () => {
       expect(response.status).toBe(201);
       const body = await parseResponse(response, tagResponseSchema);
       expect(body.name).toBe("important"); // 正規化される
+      expect(body.color).toBe(defaultTagColor("important"));
+      expect(TAG.DEFAULT_COLORS).toContain(body.color);
+    });
+
+    it("正常系: colorにnullを明示した場合は色なしで作成", async () => {
+      const response = await app.request("/api/v1/tags", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ name: "no-color", color: null }),
+      });
+
+      expect(response.status).toBe(201);
+      const body = await parseResponse(response, tagResponseSchema);
       expect(body.color).toBeNull();
     });
 
@@ -142,6 +204,46 @@ describe("タグAPI", () => {
       expect(response.status).toBe(409);
       const body = await parseResponse(response, errorResponseSchema);
       expect(body.error.code).toBe("CONFLICT");
+      expect(body.error.details?.name).toEqual([body.error.message]);
+    });
+
+    it("異常系: アクセント記号・大文字小文字のみ異なる名前で409エラー", async () => {
+      await app.request("/api/v1/tags", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ name: "café" }),
+      });
+
+      const response = await app.request("/api/v1/tags", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ name: "cafe" }),
+      });
+
+      expect(response.status).toBe(409);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("CONFLICT");
+    });
+
+    it("異常系: 未知のフィールドで400エラー", async () => {
+      const response = await app.request("/api/v1/tags", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ name: "typo", colour: "#FF0000" }),
+      });
+
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.details).toHaveProperty("colour");
     });
 
     it("異常系: 名前が空で400エラー", async () => {
@@ -174,6 +276,21 @@ describe("タグAPI", () => {
       expect(body.error.code).toBe("VALIDATION_ERROR");
     });
 
+    it("正常系: #RGB 形式の色は #RRGGBB 形式に正規化して保存する", async () => {
+      const response = await app.request("/api/v1/tags", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ name: "shorthand", color: "#F00" }),
+      });
+
+      expect(response.status).toBe(201);
+      const body = await parseResponse(response, tagResponseSchema);
+      expect(body.color).toBe("#FF0000");
+    });
+
     it("異常系: 無効な色形式で400エラー", async () => {
       const response = await app.request("/api/v1/tags", {
         method: "POST",
@@ -223,6 +340,67 @@ describe("タグAPI", () => {
     });
   });
 
+  describe("GET /api/v1/tags/:id/todos - タグ別Todo一覧", () => {
+    it("正常系: タグが付いたTodoのみ取得できる", async () => {
+      const tagResponse = await app.request("/api/v1/tags", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ name: "work" }),
+      });
+      const tag = await parseResponse(tagResponse, tagResponseSchema);
+
+      for (const [title, tagIds] of [
+        ["Tagged 1", [tag.id]],
+        ["Untagged", []],
+        ["Tagged 2", [tag.id]],
+      ] as const) {
+        await app.request("/api/v1/todos", {
+          method: "POST",
+          headers: {
+            "Content-Type": "application/json",
+            Authorization: `Bearer ${token}`,
+          },
+          body: JSON.stringify({ title, tag_ids: tagIds }),
+        });
+      }
+
+      const response = await app.request(`/api/v1/tags/${tag.id}/todos?per_page=1`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, tagTodosResponseSchema);
+      expect(body.data).toHaveLength(1);
+      expect(body.data[0].tags.map((t) => t.id)).toContain(tag.id);
+      expect(body.meta.total).toBe(2);
+      expect(body.meta.total_pages).toBe(2);
+    });
+
+    it("異常系: 他ユーザーのタグで404エラー", async () => {
+      const otherToken = await createUserAndGetToken("another@example.com");
+      const tagResponse = await app.request("/api/v1/tags", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${otherToken}`,
+        },
+        body: JSON.stringify({ name: "others" }),
+      });
+      const tag = await parseResponse(tagResponse, tagResponseSchema);
+
+      const response = await app.request(`/api/v1/tags/${tag.id}/todos`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(404);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("NOT_FOUND");
+    });
+  });
+
   describe("PATCH /api/v1/tags/:id - タグ更新", () => {
     it("正常系: タグを更新できる", async () => {
       const createResponse = await app.request("/api/v1/tags", {
@@ -307,6 +485,7 @@ describe("タグAPI", () => {
       expect(response.status).toBe(409);
       const body = await parseResponse(response, errorResponseSchema);
       expect(body.error.code).toBe("CONFLICT");
+      expect(body.error.details?.name).toEqual([body.error.message]);
     });
 
     it("異常系: 存在しないIDで404エラー", async () => {
@@ -4,6 +4,8 @@ import { errorResponseSchema, todoResponseSchema } from "../src/shared/validator
 import {
   attachTagToTodo,
   createTestCategory,
+  createTestComment,
+  createTestFile,
   createTestTag,
   createTestTodo,
   createTestUser,
@@ -14,9 +16,17 @@ import { z } from "zod";
 
 const app = createApp();
 
+/** 検索結果のTodoのスキーマ（キーワード指定時のみ highlight を含む） */
+const todoSearchResultSchema = todoResponseSchema.extend({
+  highlight: z
+    .object({ field: z.enum(["title", "description"]), snippet: z.string() })
+    .nullable()
+    .optional(),
+});
+
 /** 検索レスポンスのスキーマ */
 const todoSearchResponseSchema = z.object({
-  data: z.array(todoResponseSchema),
+  data: z.array(todoSearchResultSchema),
   meta: z.object({
     total: z.number(),
     current_page: z.number(),
@@ -24,6 +34,11 @@ const todoSearchResponseSchema = z.object({
     per_page: z.number(),
     search_query: z.string().optional(),
     filters_applied: z.record(z.string(), z.unknown()),
+    facets: z.object({
+      status: z.record(z.string(), z.number()),
+      priority: z.record(z.string(), z.number()),
+      category: z.array(z.object({ category_id: z.number().nullable(), count: z.number() })),
+    }),
   }),
   suggestions: z
     .array(
@@ -141,6 +156,147 @@ describe("Todo Search API", () => {
       const body = await parseResponse(response, todoSearchResponseSchema);
       expect(body.data).toHaveLength(2);
     });
+
+    it("正常系: キーワード指定時は一致箇所のハイライトを返す", async () => {
+      const description = `${"前置き".repeat(20)}ここが重要な部分です${"後書き".repeat(20)}`;
+      await createTestTodo({ userId, title: "重要な会議", description, position: 0 });
+      await createTestTodo({ userId, title: "重要な買い物", position: 1 });
+
+      const response = await app.request("/api/v1/todos/search?q=重要", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      const [withDescription, titleOnly] = body.data;
+
+      // 説明に一致した場合は一致箇所周辺の抜粋を返す
+      expect(withDescription.highlight?.field).toBe("description");
+      expect(withDescription.highlight?.snippet).toContain("ここが重要な部分です");
+      expect(withDescription.highlight?.snippet.startsWith("…")).toBe(true);
+      expect(withDescription.highlight?.snippet.endsWith("…")).toBe(true);
+      expect(withDescription.highlight?.snippet.length).toBeLessThan(description.length);
+
+      // タイトルのみに一致した場合はタイトルを返す
+      expect(titleOnly.highlight).toEqual({ field: "title", snippet: "重要な買い物" });
+    });
+
+    it("正常系: キーワード未指定時はハイライトを含めない", async () => {
+      await createTestTodo({ userId, title: "タスク", description: "説明" });
+
+      const response = await app.request("/api/v1/todos/search", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.data[0]).not.toHaveProperty("highlight");
+    });
+  });
+
+  describe("GET /api/v1/todos/search - コメント投稿者", () => {
+    it("正常系: 指定ユーザーが削除されていないコメントを投稿したTodoを返す", async () => {
+      const otherUser = await createTestUser("other@example.com");
+      const mine = await createTestTodo({ userId, title: "自分がコメント", position: 0 });
+      const others = await createTestTodo({ userId, title: "他ユーザーがコメント", position: 1 });
+      const deleted = await createTestTodo({ userId, title: "削除済みコメント", position: 2 });
+      await createTestTodo({ userId, title: "コメントなし", position: 3 });
+      await createTestComment({ userId, todoId: mine });
+      await createTestComment({ userId: otherUser.userId, todoId: others });
+      await createTestComment({ userId, todoId: deleted, deletedAt: new Date() });
+
+      const response = await app.request(`/api/v1/todos/search?commented_by=${userId}`, {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.data.map((t) => t.title)).toEqual(["自分がコメント"]);
+      expect(body.meta.filters_applied.commented_by).toBe(userId);
+    });
+
+    it("異常系: 存在しないユーザーIDを指定すると400エラー", async () => {
+      const response = await app.request("/api/v1/todos/search?commented_by=999999", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.details?.commented_by).toEqual(["指定されたユーザーが存在しません"]);
+    });
+  });
+
+  describe("GET /api/v1/todos/search - ファイル名検索", () => {
+    it("正常系: 添付ファイル名の部分一致で検索", async () => {
+      const todoWithFile = await createTestTodo({ userId, title: "見積もり", position: 0 });
+      const otherTodo = await createTestTodo({ userId, title: "議事録", position: 1 });
+      await createTestTodo({ userId, title: "添付なし", position: 2 });
+      await createTestFile({ userId, todoId: todoWithFile, filename: "Invoice_2024.pdf" });
+      await createTestFile({ userId, todoId: otherTodo, filename: "minutes.docx" });
+
+      const response = await app.request("/api/v1/todos/search?file_name=invoice", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.data).toHaveLength(1);
+      expect(body.data[0].title).toBe("見積もり");
+      expect(body.meta.filters_applied.file_name).toBe("invoice");
+    });
+
+    it("正常系: has_files で添付ファイルの有無を絞り込み、他の条件と組み合わせられる", async () => {
+      const withFile = await createTestTodo({ userId, title: "添付あり", position: 0 });
+      await createTestTodo({ userId, title: "添付なし", position: 1 });
+      const starredWithFile = await createTestTodo({
+        userId,
+        title: "スター付き添付あり",
+        position: 2,
+        starred: true,
+      });
+      await createTestFile({ userId, todoId: withFile, filename: "a.pdf" });
+      await createTestFile({ userId, todoId: withFile, filename: "b.pdf" });
+      await createTestFile({ userId, todoId: starredWithFile, filename: "c.pdf" });
+
+      const search = async (query: string) => {
+        const response = await app.request(`/api/v1/todos/search?${query}`, {
+          method: "GET",
+          headers: { Authorization: `Bearer ${token}` },
+        });
+        expect(response.status).toBe(200);
+        return parseResponse(response, todoSearchResponseSchema);
+      };
+
+      const withFiles = await search("has_files=true");
+      expect(withFiles.data.map((t) => t.title)).toEqual(["添付あり", "スター付き添付あり"]);
+      expect(withFiles.meta.filters_applied.has_files).toBe(true);
+
+      const withoutFiles = await search("has_files=false");
+      expect(withoutFiles.data.map((t) => t.title)).toEqual(["添付なし"]);
+      expect(withoutFiles.meta.filters_applied.has_files).toBe(false);
+
+      const combined = await search("has_files=true&starred=true");
+      expect(combined.data.map((t) => t.title)).toEqual(["スター付き添付あり"]);
+    });
+
+    it("正常系: 他ユーザーのファイルには一致しない", async () => {
+      const otherUser = await createTestUser("other@example.com");
+      const todoId = await createTestTodo({ userId, title: "Mine", position: 0 });
+      await createTestFile({ userId: otherUser.userId, todoId, filename: "invoice.pdf" });
+
+      const response = await app.request("/api/v1/todos/search?file_name=invoice", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.data).toHaveLength(0);
+    });
   });
 
   describe("GET /api/v1/todos/search - ステータスフィルター", () => {
@@ -195,6 +351,37 @@ describe("Todo Search API", () => {
       expect(body.data).toHaveLength(1);
       expect(body.data[0].priority).toBe("high");
     });
+
+    it("正常系: 複数の優先度をカンマ区切り・繰り返し・配列形式で指定できる", async () => {
+      await createTestTodo({ userId, title: "Low", priority: 0, position: 0 });
+      await createTestTodo({ userId, title: "Medium", priority: 1, position: 1 });
+      await createTestTodo({ userId, title: "High", priority: 2, position: 2 });
+
+      for (const query of [
+        "priority=low,high",
+        "priority=low&priority=high",
+        "priority[]=low&priority[]=high",
+      ]) {
+        const response = await app.request(`/api/v1/todos/search?${query}`, {
+          method: "GET",
+          headers: { Authorization: `Bearer ${token}` },
+        });
+
+        expect(response.status).toBe(200);
+        const body = await parseResponse(response, todoSearchResponseSchema);
+        expect(body.data.map((todo) => todo.title)).toEqual(["Low", "High"]);
+        expect(body.meta.filters_applied.priority).toEqual(["low", "high"]);
+      }
+    });
+
+    it("異常系: 不正な優先度が含まれる場合は400エラー", async () => {
+      const response = await app.request("/api/v1/todos/search?priority=low,urgent", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(400);
+    });
   });
 
   describe("GET /api/v1/todos/search - カテゴリフィルター", () => {
@@ -278,6 +465,27 @@ describe("Todo Search API", () => {
       expect(body.data).toHaveLength(1);
       expect(body.data[0].title).toBe("Todo 1");
     });
+
+    it("正常系: filters_appliedにタグの情報が含まれ、他ユーザーのタグは省略される", async () => {
+      const tag = await createTestTag(userId, "urgent", "#ff0000");
+      const otherUser = await createTestUser("other@example.com");
+      const othersTag = await createTestTag(otherUser.userId, "secret");
+
+      const response = await app.request(
+        `/api/v1/todos/search?tag_ids[]=${tag}&tag_ids[]=${othersTag}&tag_ids[]=999999`,
+        {
+          method: "GET",
+          headers: { Authorization: `Bearer ${token}` },
+        },
+      );
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.meta.filters_applied.tag_ids).toEqual([tag, othersTag, 999999]);
+      expect(body.meta.filters_applied.tags).toEqual([
+        { id: tag, name: "urgent", color: "#ff0000" },
+      ]);
+    });
   });
 
   describe("GET /api/v1/todos/search - 日付範囲フィルター", () => {
@@ -301,6 +509,230 @@ describe("Todo Search API", () => {
     });
   });
 
+  describe("GET /api/v1/todos/search - スヌーズ", () => {
+    it("正常系: スヌーズ中のTodoはデフォルトで除外される", async () => {
+      await createTestTodo({ userId, title: "Active", position: 0 });
+      await createTestTodo({ userId, title: "Snoozed", position: 1, snoozedUntil: "2999-12-31" });
+      await createTestTodo({ userId, title: "Woke up", position: 2, snoozedUntil: "2000-01-01" });
+
+      const response = await app.request("/api/v1/todos/search", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.data.map((t) => t.title)).toEqual(["Active", "Woke up"]);
+    });
+
+    it("正常系: include_snoozed=trueでスヌーズ中のTodoも含める", async () => {
+      await createTestTodo({ userId, title: "Active", position: 0 });
+      await createTestTodo({ userId, title: "Snoozed", position: 1, snoozedUntil: "2999-12-31" });
+
+      const response = await app.request("/api/v1/todos/search?include_snoozed=true", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.data).toHaveLength(2);
+      expect(body.meta.filters_applied.include_snoozed).toBe(true);
+    });
+  });
+
+  describe("GET /api/v1/todos/search - アーカイブ", () => {
+    it("正常系: アーカイブ済みのTodoはデフォルトで除外される", async () => {
+      await createTestTodo({ userId, title: "Active", position: 0 });
+      await createTestTodo({ userId, title: "Archived", position: 1, archivedAt: new Date() });
+
+      const response = await app.request("/api/v1/todos/search", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.data.map((t) => t.title)).toEqual(["Active"]);
+    });
+
+    it("正常系: archived=trueでアーカイブ済みのTodoを検索できる", async () => {
+      await createTestTodo({ userId, title: "Active", position: 0 });
+      await createTestTodo({ userId, title: "Archived", position: 1, archivedAt: new Date() });
+
+      const response = await app.request("/api/v1/todos/search?archived=true", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.data.map((t) => t.title)).toEqual(["Archived"]);
+      expect(body.meta.filters_applied.archived).toBe(true);
+    });
+  });
+
+  describe("GET /api/v1/todos/search - 完了日", () => {
+    it("正常系: 完了日の範囲でフィルターできる（終了日は当日を含む）", async () => {
+      await createTestTodo({
+        userId,
+        title: "Before",
+        status: 2,
+        completedAt: new Date("2025-06-01T23:00:00"),
+      });
+      await createTestTodo({
+        userId,
+        title: "In range",
+        status: 2,
+        completedAt: new Date("2025-06-05T12:00:00"),
+      });
+      await createTestTodo({
+        userId,
+        title: "Last day",
+        status: 2,
+        completedAt: new Date("2025-06-07T23:59:00"),
+      });
+      await createTestTodo({ userId, title: "Not completed" });
+
+      const response = await app.request(
+        "/api/v1/todos/search?completed_from=2025-06-02&completed_to=2025-06-07&sort_by=completed_at",
+        {
+          method: "GET",
+          headers: { Authorization: `Bearer ${token}` },
+        },
+      );
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.data.map((t) => t.title)).toEqual(["In range", "Last day"]);
+      expect(body.meta.filters_applied.completed_from).toBe("2025-06-02");
+      expect(body.meta.filters_applied.completed_to).toBe("2025-06-07");
+    });
+
+    it("正常系: completed_atで降順ソートすると未完了は最後になる", async () => {
+      await createTestTodo({ userId, title: "Open", position: 0 });
+      await createTestTodo({
+        userId,
+        title: "Old",
+        status: 2,
+        completedAt: new Date("2025-01-01T00:00:00"),
+      });
+      await createTestTodo({
+        userId,
+        title: "New",
+        status: 2,
+        completedAt: new Date("2025-02-01T00:00:00"),
+      });
+
+      const response = await app.request(
+        "/api/v1/todos/search?sort_by=completed_at&sort_order=desc",
+        {
+          method: "GET",
+          headers: { Authorization: `Bearer ${token}` },
+        },
+      );
+
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.data.map((t) => t.title)).toEqual(["New", "Old", "Open"]);
+    });
+  });
+
+  describe("GET /api/v1/todos/search - 作成日・更新日", () => {
+    it("正常系: 作成日の範囲でフィルターできる", async () => {
+      await createTestTodo({ userId, title: "Old", createdAt: new Date("2025-01-10T10:00:00") });
+      await createTestTodo({ userId, title: "Mid", createdAt: new Date("2025-02-10T10:00:00") });
+      await createTestTodo({ userId, title: "New", createdAt: new Date("2025-03-10T10:00:00") });
+
+      const response = await app.request(
+        "/api/v1/todos/search?created_from=2025-02-01&created_to=2025-02-10",
+        {
+          method: "GET",
+          headers: { Authorization: `Bearer ${token}` },
+        },
+      );
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.data.map((t) => t.title)).toEqual(["Mid"]);
+      expect(body.meta.filters_applied.created_from).toBe("2025-02-01");
+      expect(body.meta.filters_applied.created_to).toBe("2025-02-10");
+    });
+
+    it("正常系: 更新日の範囲は他のフィルターと組み合わせられる", async () => {
+      await createTestTodo({
+        userId,
+        title: "Recent high",
+        priority: 2,
+        updatedAt: new Date("2025-05-20T10:00:00"),
+      });
+      await createTestTodo({
+        userId,
+        title: "Recent low",
+        priority: 0,
+        updatedAt: new Date("2025-05-21T10:00:00"),
+      });
+      await createTestTodo({
+        userId,
+        title: "Stale high",
+        priority: 2,
+        updatedAt: new Date("2025-01-01T10:00:00"),
+      });
+
+      const response = await app.request(
+        "/api/v1/todos/search?updated_from=2025-05-01&priority=high",
+        {
+          method: "GET",
+          headers: { Authorization: `Bearer ${token}` },
+        },
+      );
+
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.data.map((t) => t.title)).toEqual(["Recent high"]);
+      expect(body.meta.filters_applied.updated_from).toBe("2025-05-01");
+    });
+  });
+
+  describe("GET /api/v1/todos/search - スターフィルター", () => {
+    it("正常系: starred=trueでスター付きのTodoのみ取得", async () => {
+      await createTestTodo({ userId, title: "Starred", position: 0, starred: true });
+      await createTestTodo({ userId, title: "Normal", position: 1 });
+
+      const response = await app.request("/api/v1/todos/search?starred=true", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.data.map((t) => t.title)).toEqual(["Starred"]);
+      expect(body.meta.filters_applied.starred).toBe(true);
+    });
+  });
+
+  describe("GET /api/v1/todos/search - ファセット", () => {
+    it("正常系: 絞り込み後の全件に対するファセットを返す", async () => {
+      const categoryId = await createTestCategory(userId, "Work");
+      await createTestTodo({ userId, title: "Task A", status: 0, priority: 2, categoryId });
+      await createTestTodo({ userId, title: "Task B", status: 2, priority: 2, categoryId });
+      await createTestTodo({ userId, title: "Task C", status: 0, priority: 0 });
+      await createTestTodo({ userId, title: "Other", status: 1, priority: 1 });
+
+      const response = await app.request("/api/v1/todos/search?q=Task&per_page=1", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.data).toHaveLength(1);
+      expect(body.meta.facets.status).toEqual({ pending: 2, in_progress: 0, completed: 1 });
+      expect(body.meta.facets.priority).toEqual({ low: 1, medium: 0, high: 2 });
+      expect(body.meta.facets.category).toHaveLength(2);
+      expect(body.meta.facets.category).toContainEqual({ category_id: categoryId, count: 2 });
+      expect(body.meta.facets.category).toContainEqual({ category_id: null, count: 1 });
+    });
+  });
+
   describe("GET /api/v1/todos/search - ソート", () => {
     it("正常系: position昇順（デフォルト）", async () => {
       await createTestTodo({ userId, title: "Third", position: 2 });
@@ -319,6 +751,22 @@ describe("Todo Search API", () => {
       expect(body.data[2].title).toBe("Third");
     });
 
+    it("正常系: ピン留めしたTodoはソート条件に関わらず先頭に並ぶ", async () => {
+      await createTestTodo({ userId, title: "High", priority: 2, position: 0 });
+      await createTestTodo({ userId, title: "Pinned Low", priority: 0, position: 1, pinned: true });
+      await createTestTodo({ userId, title: "Medium", priority: 1, position: 2 });
+
+      const response = await app.request("/api/v1/todos/search?sort_by=priority&sort_order=desc", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.data.map((t) => t.title)).toEqual(["Pinned Low", "High", "Medium"]);
+      expect(body.data[0].pinned).toBe(true);
+    });
+
     it("正常系: priority降順でソート", async () => {
       await createTestTodo({ userId, title: "Low", priority: 0, position: 0 });
       await createTestTodo({ userId, title: "High", priority: 2, position: 1 });
@@ -352,6 +800,42 @@ describe("Todo Search API", () => {
       expect(body.data[1].title).toBe("Late");
       expect(body.data[2].title).toBe("No Date");
     });
+
+    it("正常系: relevanceでキーワード一致度の高い順", async () => {
+      await createTestTodo({ userId, title: "meeting room", position: 0 });
+      await createTestTodo({
+        userId,
+        title: "meeting",
+        description: "meeting agenda for the weekly meeting",
+        position: 1,
+      });
+
+      const response = await app.request("/api/v1/todos/search?q=meeting&sort_by=relevance", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.data).toHaveLength(2);
+      expect(body.data[0].title).toBe("meeting");
+      expect(body.data[1].title).toBe("meeting room");
+    });
+
+    it("正常系: キーワードなしのrelevanceは作成日時の降順にフォールバック", async () => {
+      await createTestTodo({ userId, title: "Older", position: 0 });
+      await createTestTodo({ userId, title: "Newer", position: 1 });
+
+      const response = await app.request("/api/v1/todos/search?sort_by=relevance", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoSearchResponseSchema);
+      expect(body.data[0].title).toBe("Newer");
+      expect(body.data[1].title).toBe("Older");
+    });
   });
 
   describe("GET /api/v1/todos/search - ページネーション", () => {
@@ -389,6 +873,17 @@ describe("Todo Search API", () => {
       expect(body.meta.current_page).toBe(2);
       expect(body.data[0].title).toBe("Todo 5");
     });
+
+    it("異常系: per_page が上限（MAX_PAGE_SIZE）を超える場合は400エラー", async () => {
+      const response = await app.request("/api/v1/todos/search?per_page=101", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.details?.per_page).toEqual(["per_pageは100以下で指定してください"]);
+    });
   });
 
   describe("GET /api/v1/todos/search - 複合条件", () => {
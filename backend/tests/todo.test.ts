@@ -1,11 +1,34 @@
 import { afterAll, beforeAll, beforeEach, describe, expect, it } from "vitest";
+import { TodoService } from "../src/features/todo/service";
+import { TodoCategoryRepository } from "../src/features/todo/todo-category-repository";
+import { TodoTagValidatorRepository } from "../src/features/todo/todo-tag-validator-repository";
 import { createApp } from "../src/lib/app";
+import { getRepositoryFactories, getTodoRepository } from "../src/lib/container";
+import { todayIn } from "../src/lib/date";
+import { getDb } from "../src/lib/db";
+import { idempotencyKeys } from "../src/models/schema";
 import {
+  agendaResponseSchema,
+  archiveCompletedTodosResponseSchema,
+  bulkTagsResponseSchema,
+  categoryResponseSchema,
+  deleteCompletedTodosResponseSchema,
+  envelopeResponseSchema,
   errorResponseSchema,
   todoListResponseSchema,
   todoResponseSchema,
+  todoTrendsResponseSchema,
+  todoWithNeighborsResponseSchema,
 } from "../src/shared/validators/responses";
-import { createTestCategory, createTestTag, createTestUser } from "./helpers/factory";
+import {
+  attachLabelToTodo,
+  attachTagToTodo,
+  createTestCategory,
+  createTestLabel,
+  createTestTag,
+  createTestTodo,
+  createTestUser,
+} from "./helpers/factory";
 import { parseResponse } from "./helpers/response";
 import { clearDatabase } from "./setup";
 
@@ -82,11 +105,11 @@ describe("Todo API", () => {
       const body = await parseResponse(response, todoListResponseSchema);
       expect(body).toHaveLength(3);
       expect(body[0].title).toBe("Todo 1");
-      expect(body[0].position).toBe(0);
+      expect(body[0].position).toBe(1000);
       expect(body[1].title).toBe("Todo 2");
-      expect(body[1].position).toBe(1);
+      expect(body[1].position).toBe(2000);
       expect(body[2].title).toBe("Todo 3");
-      expect(body[2].position).toBe(2);
+      expect(body[2].position).toBe(3000);
     });
 
     it("正常系: 他ユーザーのTodoは含まれない", async () => {
@@ -134,6 +157,69 @@ describe("Todo API", () => {
       const body = await parseResponse(response, errorResponseSchema);
       expect(body.error.code).toBe("UNAUTHORIZED");
     });
+
+    it("正常系: envelope=trueで{data, meta}形式で取得できる", async () => {
+      await createTestTodo({ userId, title: "Todo 1", position: 0 });
+      await createTestTodo({ userId, title: "Todo 2", position: 1 });
+
+      const response = await app.request("/api/v1/todos?envelope=true", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, envelopeResponseSchema(todoResponseSchema));
+      expect(body.data.map((t) => t.title)).toEqual(["Todo 1", "Todo 2"]);
+      expect(body.meta.total).toBe(2);
+    });
+
+    it("正常系: ピン留めしたTodoを先頭に返す", async () => {
+      await createTestTodo({ userId, title: "Todo 1", position: 0 });
+      await createTestTodo({ userId, title: "Todo 2", position: 1 });
+      await createTestTodo({ userId, title: "Pinned", position: 2, pinned: true });
+
+      const response = await app.request("/api/v1/todos", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      const body = await parseResponse(response, todoListResponseSchema);
+      expect(body.map((t) => t.title)).toEqual(["Pinned", "Todo 1", "Todo 2"]);
+    });
+  });
+
+  describe("HEAD /api/v1/todos - Todo件数取得", () => {
+    it("正常系: 一覧と同じ条件の件数を X-Total-Count で返し、ボディは返さない", async () => {
+      await createTestTodo({ userId, title: "Todo 1" });
+      await createTestTodo({ userId, title: "Todo 2" });
+      await createTestTodo({ userId, title: "Archived", archivedAt: new Date() });
+
+      const response = await app.request("/api/v1/todos", {
+        method: "HEAD",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      expect(response.headers.get("X-Total-Count")).toBe("2");
+      expect(await response.text()).toBe("");
+    });
+
+    it("正常系: archived=true の場合はアーカイブ済みの件数を返す", async () => {
+      await createTestTodo({ userId, title: "Todo" });
+      await createTestTodo({ userId, title: "Archived", archivedAt: new Date() });
+
+      const response = await app.request("/api/v1/todos?archived=true", {
+        method: "HEAD",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.headers.get("X-Total-Count")).toBe("1");
+    });
+
+    it("異常系: 認証なしの場合は401を返す", async () => {
+      const response = await app.request("/api/v1/todos", { method: "HEAD" });
+      expect(response.status).toBe(401);
+    });
   });
 
   describe("GET /api/v1/todos/:id - Todo詳細取得", () => {
@@ -178,6 +264,7 @@ describe("Todo API", () => {
       expect(body.due_date).toBe("2025-12-31");
       expect(body.category).not.toBeNull();
       expect(body.category?.id).toBe(categoryId);
+      expect(body.effective_color).toBe(body.category?.color);
       expect(body.tags).toHaveLength(1);
       expect(body.tags[0].id).toBe(tagId);
     });
@@ -216,6 +303,164 @@ describe("Todo API", () => {
 
       expect(response.status).toBe(404);
     });
+
+    it("正常系: ETagを返し、If-None-Match が一致する場合は304", async () => {
+      const todoId = await createTestTodo({ userId, title: "Cached" });
+
+      const first = await app.request(`/api/v1/todos/${todoId}`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      expect(first.status).toBe(200);
+      const etag = first.headers.get("ETag");
+      expect(etag).toMatch(/^W\/"/);
+
+      const second = await app.request(`/api/v1/todos/${todoId}`, {
+        headers: { Authorization: `Bearer ${token}`, "If-None-Match": etag ?? "" },
+      });
+      expect(second.status).toBe(304);
+      expect(second.headers.get("ETag")).toBe(etag);
+      expect(await second.text()).toBe("");
+    });
+
+    it("正常系: 更新後は古いETagでも200で最新の内容を返す", async () => {
+      const todoId = await createTestTodo({
+        userId,
+        title: "Before",
+        updatedAt: new Date("2025-01-01T00:00:00Z"),
+      });
+
+      const first = await app.request(`/api/v1/todos/${todoId}`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const etag = first.headers.get("ETag") ?? "";
+
+      await app.request(`/api/v1/todos/${todoId}`, {
+        method: "PATCH",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({ title: "After" }),
+      });
+
+      const response = await app.request(`/api/v1/todos/${todoId}`, {
+        headers: { Authorization: `Bearer ${token}`, "If-None-Match": etag },
+      });
+      expect(response.status).toBe(200);
+      expect(response.headers.get("ETag")).not.toBe(etag);
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.title).toBe("After");
+    });
+
+    it("正常系: bulk_tagsでタグを付けた後は古いETagでも200を返す", async () => {
+      const todoId = await createTestTodo({ userId, title: "Tagged" });
+      const tagId = await createTestTag(userId);
+
+      const first = await app.request(`/api/v1/todos/${todoId}`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const etag = first.headers.get("ETag") ?? "";
+
+      await app.request("/api/v1/todos/bulk_tags", {
+        method: "POST",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({ todo_ids: [todoId], add_tag_ids: [tagId] }),
+      });
+
+      const response = await app.request(`/api/v1/todos/${todoId}`, {
+        headers: { Authorization: `Bearer ${token}`, "If-None-Match": etag },
+      });
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.tags.map((tag) => tag.id)).toEqual([tagId]);
+    });
+
+    it("正常系: 付いているタグの名前を変更した後は古いETagでも200を返す", async () => {
+      const todoId = await createTestTodo({ userId, title: "Tagged" });
+      const tagId = await createTestTag(userId, "before");
+      await app.request("/api/v1/todos/bulk_tags", {
+        method: "POST",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({ todo_ids: [todoId], add_tag_ids: [tagId] }),
+      });
+
+      const first = await app.request(`/api/v1/todos/${todoId}`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const etag = first.headers.get("ETag") ?? "";
+
+      await app.request(`/api/v1/tags/${tagId}`, {
+        method: "PATCH",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({ name: "after" }),
+      });
+
+      const response = await app.request(`/api/v1/todos/${todoId}`, {
+        headers: { Authorization: `Bearer ${token}`, "If-None-Match": etag },
+      });
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.tags[0]?.name).toBe("after");
+    });
+
+    it("正常系: X-Timezone が異なる場合は同じETagでも200を返す", async () => {
+      const todoId = await createTestTodo({ userId, title: "Zoned" });
+
+      const first = await app.request(`/api/v1/todos/${todoId}`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const etag = first.headers.get("ETag") ?? "";
+
+      const response = await app.request(`/api/v1/todos/${todoId}`, {
+        headers: {
+          Authorization: `Bearer ${token}`,
+          "If-None-Match": etag,
+          "X-Timezone": "Asia/Tokyo",
+        },
+      });
+      expect(response.status).toBe(200);
+      expect(response.headers.get("ETag")).not.toBe(etag);
+    });
+
+    it("正常系: context=position で一覧の前後のTodoのIDを返す", async () => {
+      const first = await createTestTodo({ userId, title: "First", position: 0 });
+      const second = await createTestTodo({ userId, title: "Second", position: 1 });
+      await createTestTodo({ userId, title: "Archived", position: 2, archivedAt: new Date() });
+      await createTestTodo({ userId, title: "Snoozed", position: 3, snoozedUntil: "2999-01-01" });
+      const third = await createTestTodo({ userId, title: "Third", position: 4 });
+
+      const fetchWithContext = async (id: number) => {
+        const response = await app.request(`/api/v1/todos/${id}?context=position`, {
+          headers: { Authorization: `Bearer ${token}` },
+        });
+        expect(response.status).toBe(200);
+        expect(response.headers.get("ETag")).toBeNull();
+        return parseResponse(response, todoWithNeighborsResponseSchema);
+      };
+
+      expect(await fetchWithContext(first)).toMatchObject({ prev_id: null, next_id: second });
+      expect(await fetchWithContext(second)).toMatchObject({ prev_id: first, next_id: third });
+      expect(await fetchWithContext(third)).toMatchObject({ prev_id: second, next_id: null });
+    });
+
+    it("正常系: context を指定しない場合は prev_id / next_id を含めない", async () => {
+      const todoId = await createTestTodo({ userId, title: "Todo" });
+
+      const response = await app.request(`/api/v1/todos/${todoId}`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      const body = await response.json();
+      expect(body).not.toHaveProperty("prev_id");
+      expect(body).not.toHaveProperty("next_id");
+    });
+
+    it("異常系: 不正な context は400エラー", async () => {
+      const todoId = await createTestTodo({ userId, title: "Todo" });
+
+      const response = await app.request(`/api/v1/todos/${todoId}?context=due_date`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(400);
+    });
   });
 
   describe("POST /api/v1/todos - Todo作成", () => {
@@ -237,6 +482,7 @@ describe("Todo API", () => {
       expect(body.status).toBe("pending"); // デフォルト
       expect(body.position).toBe(0);
       expect(body.category).toBeNull();
+      expect(body.effective_color).toBe("#F59E0B"); // mediumの既定色
       expect(body.tags).toEqual([]);
     });
 
@@ -273,7 +519,7 @@ describe("Todo API", () => {
       expect(body.tags).toHaveLength(2);
     });
 
-    it("正常系: positionが自動設定される", async () => {
+    it("正常系: positionが間隔を空けて自動設定される", async () => {
       const res1 = await app.request("/api/v1/todos", {
         method: "POST",
         headers: {
@@ -294,8 +540,8 @@ describe("Todo API", () => {
       });
       const todo2 = await parseResponse(res2, todoResponseSchema);
 
-      expect(todo1.position).toBe(0);
-      expect(todo2.position).toBe(1);
+      expect(todo1.position).toBe(1000);
+      expect(todo2.position).toBe(2000);
     });
 
     it("異常系: titleが空で400エラー", async () => {
@@ -313,13 +559,7 @@ describe("Todo API", () => {
       expect(body.error.code).toBe("VALIDATION_ERROR");
     });
 
-    it("異常系: 他ユーザーのCategoryで403エラー", async () => {
-      const otherUser = await createTestUser("todo-other@example.com");
-      const otherCategoryId = await createTestCategory(
-        otherUser.userId,
-        "Other Category",
-      );
-
+    it("異常系: 複数フィールドの不正をフィールドごとに返す", async () => {
       const response = await app.request("/api/v1/todos", {
         method: "POST",
         headers: {
@@ -327,147 +567,533 @@ describe("Todo API", () => {
           Authorization: `Bearer ${token}`,
         },
         body: JSON.stringify({
-          title: "Test",
-          category_id: otherCategoryId,
+          title: "a".repeat(256),
+          priority: "urgent",
+          status: "done",
+          due_date: "2025/01/01",
         }),
       });
 
-      expect(response.status).toBe(403);
+      expect(response.status).toBe(400);
       const body = await parseResponse(response, errorResponseSchema);
-      expect(body.error.code).toBe("FORBIDDEN");
+      expect(body.error.code).toBe("VALIDATION_ERROR");
+      expect(body.error.details).toEqual({
+        title: ["タイトルは255文字以内で入力してください"],
+        priority: ["優先度は low, medium, high のいずれかを指定してください"],
+        status: ["ステータスは pending, in_progress, completed のいずれかを指定してください"],
+        due_date: ["日付はYYYY-MM-DD形式で入力してください"],
+      });
     });
 
-    it("異常系: 他ユーザーのTagで403エラー", async () => {
-      const otherUser = await createTestUser("todo-other@example.com");
-      const otherTagId = await createTestTag(otherUser.userId, "Other Tag");
-
+    it("異常系: titleがない場合はtitleのエラーを返す", async () => {
       const response = await app.request("/api/v1/todos", {
         method: "POST",
         headers: {
           "Content-Type": "application/json",
           Authorization: `Bearer ${token}`,
         },
-        body: JSON.stringify({
-          title: "Test",
-          tag_ids: [otherTagId],
-        }),
+        body: JSON.stringify({ priority: "high" }),
       });
 
-      expect(response.status).toBe(403);
+      expect(response.status).toBe(400);
       const body = await parseResponse(response, errorResponseSchema);
-      expect(body.error.code).toBe("FORBIDDEN");
+      expect(body.error.details).toEqual({ title: ["タイトルは必須です"] });
     });
-  });
 
-  describe("PATCH /api/v1/todos/:id - Todo更新", () => {
-    it("正常系: 部分更新", async () => {
-      const createResponse = await app.request("/api/v1/todos", {
+    it("異常系: 未知のフィールドで400エラー（フィールド名を返す）", async () => {
+      const response = await app.request("/api/v1/todos", {
         method: "POST",
         headers: {
           "Content-Type": "application/json",
           Authorization: `Bearer ${token}`,
         },
-        body: JSON.stringify({ title: "Original Title" }),
+        body: JSON.stringify({ title: "Typo", titel: "Typo" }),
       });
-      const created = await parseResponse(createResponse, todoResponseSchema);
 
-      const response = await app.request(`/api/v1/todos/${created.id}`, {
-        method: "PATCH",
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("VALIDATION_ERROR");
+      expect(body.error.details).toHaveProperty("titel");
+    });
+
+    it("異常系: 途中で切れたJSONで400 MALFORMED_JSON（位置を返す）", async () => {
+      const response = await app.request("/api/v1/todos", {
+        method: "POST",
         headers: {
           "Content-Type": "application/json",
           Authorization: `Bearer ${token}`,
         },
-        body: JSON.stringify({ title: "Updated Title" }),
+        body: '{"title": "テスト',
       });
 
-      expect(response.status).toBe(200);
-      const body = await parseResponse(response, todoResponseSchema);
-      expect(body.title).toBe("Updated Title");
-      expect(body.priority).toBe("medium"); // 変更なし
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("MALFORMED_JSON");
+      // 日本語はUTF-8で1文字3バイトのため、末尾の位置は 11 + 3 * 3 = 20 バイト目
+      expect(body.error.details).toEqual({ body: ["20バイト目で構文エラーが発生しました"] });
     });
 
-    it("正常系: completed更新", async () => {
-      const createResponse = await app.request("/api/v1/todos", {
+    it("異常系: 構文エラーの位置が分かる場合はその位置を返す", async () => {
+      const response = await app.request("/api/v1/todos", {
         method: "POST",
         headers: {
           "Content-Type": "application/json",
           Authorization: `Bearer ${token}`,
         },
-        body: JSON.stringify({ title: "Test" }),
+        body: '{"title": "Test",}',
       });
-      const created = await parseResponse(createResponse, todoResponseSchema);
 
-      const response = await app.request(`/api/v1/todos/${created.id}`, {
-        method: "PATCH",
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("MALFORMED_JSON");
+      expect(body.error.details).toEqual({ body: ["17バイト目で構文エラーが発生しました"] });
+    });
+
+    it("異常系: 他ユーザーのCategoryで403エラー", async () => {
+      const otherUser = await createTestUser("todo-other@example.com");
+      const otherCategoryId = await createTestCategory(
+        otherUser.userId,
+        "Other Category",
+      );
+
+      const response = await app.request("/api/v1/todos", {
+        method: "POST",
         headers: {
           "Content-Type": "application/json",
           Authorization: `Bearer ${token}`,
         },
-        body: JSON.stringify({ completed: true }),
+        body: JSON.stringify({
+          title: "Test",
+          category_id: otherCategoryId,
+        }),
       });
 
-      expect(response.status).toBe(200);
-      const body = await parseResponse(response, todoResponseSchema);
-      expect(body.completed).toBe(true);
+      expect(response.status).toBe(403);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("FORBIDDEN");
     });
 
-    it("正常系: タグの差し替え", async () => {
-      const tagId1 = await createTestTag(userId, "Tag 1");
-      const tagId2 = await createTestTag(userId, "Tag 2");
+    it("異常系: 他ユーザーのTagで400エラー", async () => {
+      const otherUser = await createTestUser("todo-other@example.com");
+      const otherTagId = await createTestTag(otherUser.userId, "Other Tag");
 
-      const createResponse = await app.request("/api/v1/todos", {
+      const response = await app.request("/api/v1/todos", {
         method: "POST",
         headers: {
           "Content-Type": "application/json",
           Authorization: `Bearer ${token}`,
         },
-        body: JSON.stringify({ title: "Test", tag_ids: [tagId1] }),
+        body: JSON.stringify({
+          title: "Test",
+          tag_ids: [otherTagId],
+        }),
       });
-      const created = await parseResponse(createResponse, todoResponseSchema);
-      expect(created.tags).toHaveLength(1);
-      expect(created.tags[0].id).toBe(tagId1);
 
-      const response = await app.request(`/api/v1/todos/${created.id}`, {
-        method: "PATCH",
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("VALIDATION_ERROR");
+    });
+
+    it("異常系: 使用できないタグIDがエラー詳細に列挙され、Todoは作成されない", async () => {
+      const tagId = await createTestTag(userId, "Mine");
+      const otherUser = await createTestUser("todo-other@example.com");
+      const otherTagId = await createTestTag(otherUser.userId, "Other Tag");
+
+      const response = await app.request("/api/v1/todos", {
+        method: "POST",
         headers: {
           "Content-Type": "application/json",
           Authorization: `Bearer ${token}`,
         },
-        body: JSON.stringify({ tag_ids: [tagId2] }),
+        body: JSON.stringify({
+          title: "Test",
+          tag_ids: [tagId, otherTagId, 999999],
+        }),
       });
 
-      expect(response.status).toBe(200);
-      const body = await parseResponse(response, todoResponseSchema);
-      expect(body.tags).toHaveLength(1);
-      expect(body.tags[0].id).toBe(tagId2);
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.details?.tag_ids).toEqual([
+        `ID ${otherTagId} は存在しないか、使用できません`,
+        "ID 999999 は存在しないか、使用できません",
+      ]);
+
+      const listResponse = await app.request("/api/v1/todos", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const list = await parseResponse(listResponse, todoListResponseSchema);
+      expect(list).toHaveLength(0);
     });
 
-    it("異常系: 存在しないIDで404エラー", async () => {
-      const response = await app.request("/api/v1/todos/99999", {
-        method: "PATCH",
+    it("正常系: label_ids を指定してラベル付きで作成", async () => {
+      const bugId = await createTestLabel(userId, "bug", "#d73a4a");
+
+      const response = await app.request("/api/v1/todos", {
+        method: "POST",
         headers: {
           "Content-Type": "application/json",
           Authorization: `Bearer ${token}`,
         },
-        body: JSON.stringify({ title: "Updated" }),
+        body: JSON.stringify({ title: "Labeled", label_ids: [bugId] }),
       });
 
-      expect(response.status).toBe(404);
+      expect(response.status).toBe(201);
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.labels).toEqual([{ id: bugId, name: "bug", color: "#d73a4a" }]);
     });
 
-    it("異常系: 他ユーザーのTodoで404エラー", async () => {
+    it("異常系: 他ユーザーのラベルIDで400エラー", async () => {
       const otherUser = await createTestUser("todo-other@example.com");
-      const createResponse = await app.request("/api/v1/todos", {
+      const otherLabelId = await createTestLabel(otherUser.userId, "other");
+
+      const response = await app.request("/api/v1/todos", {
         method: "POST",
         headers: {
           "Content-Type": "application/json",
-          Authorization: `Bearer ${otherUser.token}`,
+          Authorization: `Bearer ${token}`,
         },
-        body: JSON.stringify({ title: "Other's todo" }),
+        body: JSON.stringify({ title: "Test", label_ids: [otherLabelId] }),
       });
-      const created = await parseResponse(createResponse, todoResponseSchema);
 
-      const response = await app.request(`/api/v1/todos/${created.id}`, {
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.details?.label_ids).toEqual([
+        `ID ${otherLabelId} は存在しないか、使用できません`,
+      ]);
+    });
+
+    it("正常系: position を指定すると既存のTodoの間に挿入し、以降のTodoをずらす", async () => {
+      await createTestTodo({ userId, title: "First", position: 1000 });
+      await createTestTodo({ userId, title: "Second", position: 2000 });
+      await createTestTodo({ userId, title: "Third", position: 3000 });
+
+      const response = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({ title: "Inserted", position: 2000 }),
+      });
+      expect(response.status).toBe(201);
+      expect((await parseResponse(response, todoResponseSchema)).position).toBe(2000);
+
+      const list = await app.request("/api/v1/todos", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const body = await parseResponse(list, todoListResponseSchema);
+      expect(body.map((t) => [t.title, t.position])).toEqual([
+        ["First", 1000],
+        ["Inserted", 2000],
+        ["Second", 3000],
+        ["Third", 4000],
+      ]);
+    });
+
+    it("正常系: position 指定でずれたTodoは updated_at が変わらず、元の Last-Modified で更新できる", async () => {
+      const lastModified = "Mon, 01 Jan 2024 00:00:00 GMT";
+      const neighborId = await createTestTodo({
+        userId,
+        title: "Neighbor",
+        position: 2000,
+        updatedAt: new Date(lastModified),
+      });
+
+      const createResponse = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({ title: "Inserted", position: 2000 }),
+      });
+      expect(createResponse.status).toBe(201);
+
+      const response = await app.request(`/api/v1/todos/${neighborId}`, {
+        method: "PATCH",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+          "If-Unmodified-Since": lastModified,
+        },
+        body: JSON.stringify({ title: "Updated" }),
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.position).toBe(3000);
+    });
+
+    it("異常系: 負の position は400を返す", async () => {
+      const response = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({ title: "Test", position: -1 }),
+      });
+
+      expect(response.status).toBe(400);
+    });
+  });
+
+  describe("POST /api/v1/todos - 冪等キー", () => {
+    const createWithKey = (authToken: string, key: string, title = "Idempotent Todo") =>
+      app.request("/api/v1/todos", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${authToken}`,
+          "Idempotency-Key": key,
+        },
+        body: JSON.stringify({ title }),
+      });
+
+    const listTodos = async (authToken: string) => {
+      const response = await app.request("/api/v1/todos", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${authToken}` },
+      });
+      return parseResponse(response, todoListResponseSchema);
+    };
+
+    it("正常系: 同じキーで再送すると最初に作成したTodoを返し、重複作成しない", async () => {
+      const first = await createWithKey(token, "key-1");
+      expect(first.status).toBe(201);
+      expect(first.headers.get("Idempotent-Replayed")).toBeNull();
+      const firstBody = await parseResponse(first, todoResponseSchema);
+
+      const second = await createWithKey(token, "key-1");
+      expect(second.status).toBe(201);
+      expect(second.headers.get("Idempotent-Replayed")).toBe("true");
+      const secondBody = await parseResponse(second, todoResponseSchema);
+      expect(secondBody.id).toBe(firstBody.id);
+
+      expect(await listTodos(token)).toHaveLength(1);
+    });
+
+    it("異常系: 同じキーを異なる内容で再送すると409エラーで、Todoは作成しない", async () => {
+      await createWithKey(token, "key-1");
+
+      const response = await createWithKey(token, "key-1", "Different Title");
+      expect(response.status).toBe(409);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("CONFLICT");
+      expect(body.error.details?.["idempotency-key"]).toBeDefined();
+
+      const todos = await listTodos(token);
+      expect(todos.map((todo) => todo.title)).toEqual(["Idempotent Todo"]);
+    });
+
+    it("正常系: 異なるキーではそれぞれ作成する", async () => {
+      await createWithKey(token, "key-1");
+      await createWithKey(token, "key-2");
+
+      expect(await listTodos(token)).toHaveLength(2);
+    });
+
+    it("正常系: 他ユーザーの同じキーとは区別する", async () => {
+      const otherUser = await createTestUser("idempotency-other@example.com");
+      const mine = await parseResponse(await createWithKey(token, "shared"), todoResponseSchema);
+
+      const response = await createWithKey(otherUser.token, "shared");
+      expect(response.status).toBe(201);
+      expect(response.headers.get("Idempotent-Replayed")).toBeNull();
+      const theirs = await parseResponse(response, todoResponseSchema);
+      expect(theirs.id).not.toBe(mine.id);
+    });
+
+    it("正常系: 有効期限切れのキーでは新たに作成する", async () => {
+      const first = await parseResponse(await createWithKey(token, "key-1"), todoResponseSchema);
+      await getDb()
+        .update(idempotencyKeys)
+        .set({ expiresAt: new Date(Date.now() - 1000) });
+
+      const response = await createWithKey(token, "key-1");
+      expect(response.status).toBe(201);
+      expect(response.headers.get("Idempotent-Replayed")).toBeNull();
+      const second = await parseResponse(response, todoResponseSchema);
+      expect(second.id).not.toBe(first.id);
+
+      expect(await listTodos(token)).toHaveLength(2);
+    });
+
+    it("正常系: 作成したTodoを削除した後は同じキーで新たに作成する", async () => {
+      const first = await parseResponse(await createWithKey(token, "key-1"), todoResponseSchema);
+      await app.request(`/api/v1/todos/${first.id}`, {
+        method: "DELETE",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      const response = await createWithKey(token, "key-1");
+      expect(response.status).toBe(201);
+      expect(response.headers.get("Idempotent-Replayed")).toBeNull();
+    });
+
+    it("異常系: キーが長すぎる場合は400エラー", async () => {
+      const response = await createWithKey(token, "a".repeat(256));
+
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.details?.["idempotency-key"]).toBeDefined();
+    });
+  });
+
+  describe("PATCH /api/v1/todos/:id - Todo更新", () => {
+    it("正常系: 部分更新", async () => {
+      const createResponse = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ title: "Original Title" }),
+      });
+      const created = await parseResponse(createResponse, todoResponseSchema);
+
+      const response = await app.request(`/api/v1/todos/${created.id}`, {
+        method: "PATCH",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ title: "Updated Title" }),
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.title).toBe("Updated Title");
+      expect(body.priority).toBe("medium"); // 変更なし
+    });
+
+    it("正常系: completed更新", async () => {
+      const createResponse = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ title: "Test" }),
+      });
+      const created = await parseResponse(createResponse, todoResponseSchema);
+
+      const response = await app.request(`/api/v1/todos/${created.id}`, {
+        method: "PATCH",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ completed: true }),
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.completed).toBe(true);
+    });
+
+    it("正常系: タグの差し替え", async () => {
+      const tagId1 = await createTestTag(userId, "Tag 1");
+      const tagId2 = await createTestTag(userId, "Tag 2");
+
+      const createResponse = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ title: "Test", tag_ids: [tagId1] }),
+      });
+      const created = await parseResponse(createResponse, todoResponseSchema);
+      expect(created.tags).toHaveLength(1);
+      expect(created.tags[0].id).toBe(tagId1);
+
+      const response = await app.request(`/api/v1/todos/${created.id}`, {
+        method: "PATCH",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ tag_ids: [tagId2] }),
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.tags).toHaveLength(1);
+      expect(body.tags[0].id).toBe(tagId2);
+    });
+
+    it("正常系: nullを指定するとdescriptionとdue_dateをクリアできる", async () => {
+      const createResponse = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ title: "Clear", description: "説明", due_date: "2030-01-01" }),
+      });
+      const created = await parseResponse(createResponse, todoResponseSchema);
+
+      const response = await app.request(`/api/v1/todos/${created.id}`, {
+        method: "PATCH",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ description: null, due_date: null }),
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.description).toBeNull();
+      expect(body.due_date).toBeNull();
+      expect(body.title).toBe("Clear");
+    });
+
+    it("正常系: 省略したフィールドは変更されない", async () => {
+      const createResponse = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ title: "Keep", description: "説明", due_date: "2030-01-01" }),
+      });
+      const created = await parseResponse(createResponse, todoResponseSchema);
+
+      const response = await app.request(`/api/v1/todos/${created.id}`, {
+        method: "PATCH",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ title: "Kept" }),
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.description).toBe("説明");
+      expect(body.due_date).toBe("2030-01-01");
+    });
+
+    it("異常系: 存在しないIDで404エラー", async () => {
+      const response = await app.request("/api/v1/todos/99999", {
+        method: "PATCH",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ title: "Updated" }),
+      });
+
+      expect(response.status).toBe(404);
+    });
+
+    it("異常系: 他ユーザーのTodoで404エラー", async () => {
+      const otherUser = await createTestUser("todo-other@example.com");
+      const createResponse = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${otherUser.token}`,
+        },
+        body: JSON.stringify({ title: "Other's todo" }),
+      });
+      const created = await parseResponse(createResponse, todoResponseSchema);
+
+      const response = await app.request(`/api/v1/todos/${created.id}`, {
         method: "PATCH",
         headers: {
           "Content-Type": "application/json",
@@ -476,72 +1102,1195 @@ describe("Todo API", () => {
         body: JSON.stringify({ title: "Try to update" }),
       });
 
-      expect(response.status).toBe(404);
+      expect(response.status).toBe(404);
+    });
+
+    it("正常系: label_ids でラベルを置き換え、空配列で外す", async () => {
+      const bugId = await createTestLabel(userId, "bug");
+      const docsId = await createTestLabel(userId, "docs");
+      const todoId = await createTestTodo({ userId, title: "Todo" });
+      await attachLabelToTodo(todoId, bugId);
+
+      const replace = await app.request(`/api/v1/todos/${todoId}`, {
+        method: "PATCH",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({ label_ids: [docsId] }),
+      });
+      const replaced = await parseResponse(replace, todoResponseSchema);
+      expect(replaced.labels.map((l) => l.id)).toEqual([docsId]);
+
+      const clear = await app.request(`/api/v1/todos/${todoId}`, {
+        method: "PATCH",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({ label_ids: [] }),
+      });
+      const cleared = await parseResponse(clear, todoResponseSchema);
+      expect(cleared.labels).toEqual([]);
+    });
+  });
+
+  describe("reminder_offset_minutes - リマインダーのオフセット", () => {
+    it("正常系: 作成時に指定し、更新で変更・解除できる", async () => {
+      const createResponse = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({
+          title: "Reminder",
+          due_date: "2030-01-01",
+          reminder_offset_minutes: 1440,
+        }),
+      });
+      const created = await parseResponse(createResponse, todoResponseSchema);
+      expect(created.reminder_offset_minutes).toBe(1440);
+
+      const update = await app.request(`/api/v1/todos/${created.id}`, {
+        method: "PATCH",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({ reminder_offset_minutes: 60 }),
+      });
+      expect((await parseResponse(update, todoResponseSchema)).reminder_offset_minutes).toBe(60);
+
+      const clear = await app.request(`/api/v1/todos/${created.id}`, {
+        method: "PATCH",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({ reminder_offset_minutes: null }),
+      });
+      expect((await parseResponse(clear, todoResponseSchema)).reminder_offset_minutes).toBeNull();
+    });
+
+    it("異常系: 負の値は400を返す", async () => {
+      const response = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({ title: "Reminder", reminder_offset_minutes: -1 }),
+      });
+
+      expect(response.status).toBe(400);
+    });
+
+    it("正常系: 省略した場合はnullになる", async () => {
+      const todoId = await createTestTodo({ userId, title: "Todo" });
+
+      const response = await app.request(`/api/v1/todos/${todoId}`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.reminder_offset_minutes).toBeNull();
+    });
+  });
+
+  describe("PATCH /api/v1/todos/:id - ピン留め", () => {
+    it("正常系: pinned でピン留め・解除できる", async () => {
+      const todoId = await createTestTodo({ userId, title: "Todo" });
+
+      const pin = await app.request(`/api/v1/todos/${todoId}`, {
+        method: "PATCH",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({ pinned: true }),
+      });
+      expect((await parseResponse(pin, todoResponseSchema)).pinned).toBe(true);
+
+      const unpin = await app.request(`/api/v1/todos/${todoId}`, {
+        method: "PATCH",
+        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        body: JSON.stringify({ pinned: false }),
+      });
+      expect((await parseResponse(unpin, todoResponseSchema)).pinned).toBe(false);
+    });
+
+    it("正常系: context=position の前後のTodoもピン留めを先頭にした順で返す", async () => {
+      const first = await createTestTodo({ userId, title: "First", position: 0 });
+      const pinned = await createTestTodo({ userId, title: "Pinned", position: 1, pinned: true });
+
+      const response = await app.request(`/api/v1/todos/${first}?context=position`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      const body = await parseResponse(response, todoWithNeighborsResponseSchema);
+      expect(body.prev_id).toBe(pinned);
+      expect(body.next_id).toBeNull();
+    });
+  });
+
+  describe("PATCH /api/v1/todos/:id - If-Unmodified-Since", () => {
+    it("正常系: 指定日時以降に更新されていなければ更新し、Last-Modified を返す", async () => {
+      const todoId = await createTestTodo({
+        userId,
+        title: "Original",
+        updatedAt: new Date("2024-01-01T00:00:00.500Z"),
+      });
+
+      const response = await app.request(`/api/v1/todos/${todoId}`, {
+        method: "PATCH",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+          "If-Unmodified-Since": "Mon, 01 Jan 2024 00:00:00 GMT",
+        },
+        body: JSON.stringify({ title: "Updated" }),
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.title).toBe("Updated");
+      expect(response.headers.get("Last-Modified")).toBe(new Date(body.updated_at).toUTCString());
+    });
+
+    it("異常系: 指定日時より後に更新されている場合は412を返し、更新しない", async () => {
+      const todoId = await createTestTodo({
+        userId,
+        title: "Original",
+        updatedAt: new Date("2024-01-02T00:00:00Z"),
+      });
+
+      const response = await app.request(`/api/v1/todos/${todoId}`, {
+        method: "PATCH",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+          "If-Unmodified-Since": "Mon, 01 Jan 2024 00:00:00 GMT",
+        },
+        body: JSON.stringify({ title: "Stale update" }),
+      });
+
+      expect(response.status).toBe(412);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("PRECONDITION_FAILED");
+
+      const show = await app.request(`/api/v1/todos/${todoId}`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const current = await parseResponse(show, todoResponseSchema);
+      expect(current.title).toBe("Original");
+    });
+
+    it("正常系: 日時として解釈できない値は無視する", async () => {
+      const todoId = await createTestTodo({ userId, title: "Original" });
+
+      const response = await app.request(`/api/v1/todos/${todoId}`, {
+        method: "PATCH",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+          "If-Unmodified-Since": "invalid",
+        },
+        body: JSON.stringify({ title: "Updated" }),
+      });
+
+      expect(response.status).toBe(200);
+    });
+
+    it("異常系: 他ユーザーのTodoは404", async () => {
+      const otherUser = await createTestUser("todo-other@example.com");
+      const todoId = await createTestTodo({ userId: otherUser.userId, title: "Other" });
+
+      const response = await app.request(`/api/v1/todos/${todoId}`, {
+        method: "PATCH",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+          "If-Unmodified-Since": new Date().toUTCString(),
+        },
+        body: JSON.stringify({ title: "Updated" }),
+      });
+
+      expect(response.status).toBe(404);
+    });
+  });
+
+  describe("DELETE /api/v1/todos/:id - Todo削除", () => {
+    it("正常系: 削除成功で204", async () => {
+      const createResponse = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ title: "To be deleted" }),
+      });
+      const created = await parseResponse(createResponse, todoResponseSchema);
+
+      const response = await app.request(`/api/v1/todos/${created.id}`, {
+        method: "DELETE",
+        headers: {
+          Authorization: `Bearer ${token}`,
+        },
+      });
+
+      expect(response.status).toBe(204);
+
+      // 削除確認
+      const getResponse = await app.request(`/api/v1/todos/${created.id}`, {
+        method: "GET",
+        headers: {
+          Authorization: `Bearer ${token}`,
+        },
+      });
+      expect(getResponse.status).toBe(404);
+    });
+
+    it("異常系: 存在しないIDで404エラー", async () => {
+      const response = await app.request("/api/v1/todos/99999", {
+        method: "DELETE",
+        headers: {
+          Authorization: `Bearer ${token}`,
+        },
+      });
+
+      expect(response.status).toBe(404);
+    });
+
+    it("異常系: 他ユーザーのTodoで404エラー", async () => {
+      const otherUser = await createTestUser("todo-other@example.com");
+      const createResponse = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${otherUser.token}`,
+        },
+        body: JSON.stringify({ title: "Other's todo" }),
+      });
+      const created = await parseResponse(createResponse, todoResponseSchema);
+
+      const response = await app.request(`/api/v1/todos/${created.id}`, {
+        method: "DELETE",
+        headers: {
+          Authorization: `Bearer ${token}`,
+        },
+      });
+
+      expect(response.status).toBe(404);
+    });
+  });
+
+  describe("POST /api/v1/todos/archive_completed - 完了済みTodo一括アーカイブ", () => {
+    const archiveCompleted = () =>
+      app.request("/api/v1/todos/archive_completed", {
+        method: "POST",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+    it("正常系: 未アーカイブの完了済みTodoのみアーカイブし、件数を返す", async () => {
+      await createTestTodo({ userId, title: "Done 1", status: 2 });
+      await createTestTodo({ userId, title: "Done 2", status: 2 });
+      await createTestTodo({ userId, title: "Archived", status: 2, archivedAt: new Date() });
+      await createTestTodo({ userId, title: "Pending" });
+      const other = await createTestUser("todo-other@example.com");
+      await createTestTodo({ userId: other.userId, title: "Other done", status: 2 });
+
+      const response = await archiveCompleted();
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, archiveCompletedTodosResponseSchema);
+      expect(body.archived_count).toBe(2);
+
+      const activeResponse = await app.request("/api/v1/todos", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const active = await parseResponse(activeResponse, todoListResponseSchema);
+      expect(active.map((t) => t.title)).toEqual(["Pending"]);
+
+      const archivedResponse = await app.request("/api/v1/todos?archived=true", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const archived = await parseResponse(archivedResponse, todoListResponseSchema);
+      expect(archived.map((t) => t.title).sort()).toEqual(["Archived", "Done 1", "Done 2"]);
+    });
+
+    it("正常系: 対象がない場合も200で archived_count: 0 を返す", async () => {
+      await createTestTodo({ userId, title: "Pending" });
+
+      const response = await archiveCompleted();
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, archiveCompletedTodosResponseSchema);
+      expect(body.archived_count).toBe(0);
+    });
+  });
+
+  describe("DELETE /api/v1/todos/completed - 完了済みTodo一括削除", () => {
+    const deleteCompleted = (authToken: string) =>
+      app.request("/api/v1/todos/completed", {
+        method: "DELETE",
+        headers: { Authorization: `Bearer ${authToken}` },
+      });
+
+    it("正常系: 完了済みのTodoのみ削除し、削除件数を返す", async () => {
+      await createTestTodo({ userId, title: "Done 1", status: 2 });
+      await createTestTodo({ userId, title: "Done 2", status: 2, archivedAt: new Date() });
+      await createTestTodo({ userId, title: "Pending" });
+      await createTestTodo({ userId, title: "In progress", status: 1 });
+      const other = await createTestUser("todo-other@example.com");
+      await createTestTodo({ userId: other.userId, title: "Other done", status: 2 });
+
+      const response = await deleteCompleted(token);
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, deleteCompletedTodosResponseSchema);
+      expect(body.deleted_count).toBe(2);
+
+      const listResponse = await app.request("/api/v1/todos", {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const todos = await parseResponse(listResponse, todoListResponseSchema);
+      expect(todos.map((t) => t.title).sort()).toEqual(["In progress", "Pending"]);
+
+      const otherResponse = await deleteCompleted(other.token);
+      const otherBody = await parseResponse(otherResponse, deleteCompletedTodosResponseSchema);
+      expect(otherBody.deleted_count).toBe(1);
+    });
+
+    it("正常系: 削除したTodoの件数だけカテゴリのtodos_countを減らす", async () => {
+      const categoryId = await createTestCategory(userId);
+      const createTodo = (title: string, status: string) =>
+        app.request("/api/v1/todos", {
+          method: "POST",
+          headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+          body: JSON.stringify({ title, status, category_id: categoryId }),
+        });
+      await createTodo("Done 1", "completed");
+      await createTodo("Done 2", "completed");
+      await createTodo("Pending", "pending");
+
+      await deleteCompleted(token);
+
+      const response = await app.request(`/api/v1/categories/${categoryId}`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const category = await parseResponse(response, categoryResponseSchema);
+      expect(category.todos_count).toBe(1);
+    });
+
+    it("正常系: 削除対象がない場合も200で deleted_count: 0 を返す", async () => {
+      await createTestTodo({ userId, title: "Pending" });
+
+      const response = await deleteCompleted(token);
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, deleteCompletedTodosResponseSchema);
+      expect(body.deleted_count).toBe(0);
+    });
+  });
+
+  describe("POST /api/v1/todos/:id/snooze - スヌーズ", () => {
+    /** 1週間後の日付（YYYY-MM-DD） */
+    const nextWeek = new Date(Date.now() + 7 * 24 * 60 * 60 * 1000).toISOString().slice(0, 10);
+
+    it("正常系: スヌーズしたTodoは一覧から除外される", async () => {
+      const createResponse = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ title: "Snoozed", due_date: "2030-01-01" }),
+      });
+      const created = await parseResponse(createResponse, todoResponseSchema);
+
+      const response = await app.request(`/api/v1/todos/${created.id}/snooze`, {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ until: nextWeek }),
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.snoozed_until).toBe(nextWeek);
+      expect(body.due_date).toBe("2030-01-01"); // 期限日は変更されない
+
+      const listResponse = await app.request("/api/v1/todos", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const list = await parseResponse(listResponse, todoListResponseSchema);
+      expect(list).toHaveLength(0);
+    });
+
+    it("正常系: include_snoozed=trueでスヌーズ中のTodoも取得できる", async () => {
+      const createResponse = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ title: "Snoozed" }),
+      });
+      const created = await parseResponse(createResponse, todoResponseSchema);
+
+      await app.request(`/api/v1/todos/${created.id}/snooze`, {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ until: nextWeek }),
+      });
+
+      const response = await app.request("/api/v1/todos?include_snoozed=true", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const list = await parseResponse(response, todoListResponseSchema);
+      expect(list).toHaveLength(1);
+      expect(list[0].snoozed_until).toBe(nextWeek);
+    });
+
+    it("正常系: untilにnullを指定するとスヌーズを解除できる", async () => {
+      const createResponse = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ title: "Snoozed" }),
+      });
+      const created = await parseResponse(createResponse, todoResponseSchema);
+
+      await app.request(`/api/v1/todos/${created.id}/snooze`, {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ until: nextWeek }),
+      });
+      const response = await app.request(`/api/v1/todos/${created.id}/snooze`, {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ until: null }),
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.snoozed_until).toBeNull();
+    });
+
+    it("異常系: untilが今日以前の場合は400エラー", async () => {
+      const todoId = await createTestTodo({ userId, title: "Snoozed" });
+
+      for (const until of [todayIn(), "2000-01-01"]) {
+        const response = await app.request(`/api/v1/todos/${todoId}/snooze`, {
+          method: "POST",
+          headers: {
+            "Content-Type": "application/json",
+            Authorization: `Bearer ${token}`,
+          },
+          body: JSON.stringify({ until }),
+        });
+
+        expect(response.status).toBe(400);
+        const body = await parseResponse(response, errorResponseSchema);
+        expect(body.error.details?.until).toEqual(["until には明日以降の日付を指定してください"]);
+      }
+    });
+
+    it("異常系: 他ユーザーのTodoで404エラー", async () => {
+      const otherUser = await createTestUser("todo-other@example.com");
+      const createResponse = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${otherUser.token}`,
+        },
+        body: JSON.stringify({ title: "Other's todo" }),
+      });
+      const created = await parseResponse(createResponse, todoResponseSchema);
+
+      const response = await app.request(`/api/v1/todos/${created.id}/snooze`, {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ until: nextWeek }),
+      });
+
+      expect(response.status).toBe(404);
+    });
+  });
+
+  describe("PATCH /api/v1/todos/:id - アーカイブ", () => {
+    it("正常系: アーカイブしたTodoは一覧から除外され、archived=trueで取得できる", async () => {
+      const createResponse = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ title: "Archive me", status: "in_progress" }),
+      });
+      const created = await parseResponse(createResponse, todoResponseSchema);
+
+      const response = await app.request(`/api/v1/todos/${created.id}`, {
+        method: "PATCH",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ archived: true }),
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.archived_at).not.toBeNull();
+      expect(body.status).toBe("in_progress"); // ステータスは変更されない
+      expect(body.completed).toBe(false);
+
+      const listResponse = await app.request("/api/v1/todos", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const list = await parseResponse(listResponse, todoListResponseSchema);
+      expect(list).toHaveLength(0);
+
+      const archivedResponse = await app.request("/api/v1/todos?archived=true", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const archived = await parseResponse(archivedResponse, todoListResponseSchema);
+      expect(archived).toHaveLength(1);
+      expect(archived[0].id).toBe(created.id);
+    });
+
+    it("正常系: archived=falseでアーカイブを解除できる", async () => {
+      const createResponse = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ title: "Archive me" }),
+      });
+      const created = await parseResponse(createResponse, todoResponseSchema);
+
+      for (const archived of [true, false]) {
+        await app.request(`/api/v1/todos/${created.id}`, {
+          method: "PATCH",
+          headers: {
+            "Content-Type": "application/json",
+            Authorization: `Bearer ${token}`,
+          },
+          body: JSON.stringify({ archived }),
+        });
+      }
+
+      const listResponse = await app.request("/api/v1/todos", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const list = await parseResponse(listResponse, todoListResponseSchema);
+      expect(list).toHaveLength(1);
+      expect(list[0].archived_at).toBeNull();
+    });
+  });
+
+  describe("PATCH /api/v1/todos/:id - 完了日時", () => {
+    /**
+     * Todoを更新する
+     * @param id - TodoのID
+     * @param data - 更新データ
+     * @returns 更新後のTodo
+     */
+    const patchTodo = async (id: number, data: Record<string, unknown>) => {
+      const response = await app.request(`/api/v1/todos/${id}`, {
+        method: "PATCH",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify(data),
+      });
+      return await parseResponse(response, todoResponseSchema);
+    };
+
+    it("正常系: 完了済みで作成すると completed_at が設定される", async () => {
+      const response = await app.request("/api/v1/todos", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ title: "Done", status: "completed" }),
+      });
+
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.completed_at).not.toBeNull();
+    });
+
+    it("正常系: 完了で設定され、未完了に戻すとクリアされる", async () => {
+      const todoId = await createTestTodo({ userId, title: "Task" });
+
+      const completed = await patchTodo(todoId, { completed: true });
+      expect(completed.completed_at).not.toBeNull();
+
+      // 完了済みのまま status を再指定しても完了日時は維持される
+      const again = await patchTodo(todoId, { status: "completed" });
+      expect(again.completed_at).toBe(completed.completed_at);
+
+      const reopened = await patchTodo(todoId, { status: "in_progress" });
+      expect(reopened.completed_at).toBeNull();
+    });
+
+    it("正常系: 完了に関係しない更新では completed_at は変わらない", async () => {
+      const todoId = await createTestTodo({ userId, title: "Task" });
+
+      const body = await patchTodo(todoId, { title: "Renamed" });
+      expect(body.completed_at).toBeNull();
     });
   });
 
-  describe("DELETE /api/v1/todos/:id - Todo削除", () => {
-    it("正常系: 削除成功で204", async () => {
+  describe("GET /api/v1/todos/agenda - アジェンダ", () => {
+    /** 基準日（UTC） */
+    const today = todayIn("UTC");
+    /** n日後の日付（YYYY-MM-DD） */
+    const daysFromToday = (n: number) =>
+      new Date(Date.parse(today) + n * 24 * 60 * 60 * 1000).toISOString().slice(0, 10);
+
+    it("正常系: 期限切れと今日が期限のTodoを分類して返す", async () => {
+      await createTestTodo({ userId, title: "Overdue", dueDate: daysFromToday(-3) });
+      await createTestTodo({ userId, title: "Today", dueDate: today });
+      await createTestTodo({ userId, title: "Tomorrow", dueDate: daysFromToday(1) });
+      await createTestTodo({ userId, title: "No due date" });
+      await createTestTodo({ userId, title: "Done", dueDate: today, status: 2 });
+      await createTestTodo({
+        userId,
+        title: "Archived",
+        dueDate: today,
+        archivedAt: new Date(),
+      });
+
+      const response = await app.request("/api/v1/todos/agenda?tz=UTC", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, agendaResponseSchema);
+      expect(body.date).toBe(today);
+      expect(body.timezone).toBe("UTC");
+      expect(body.overdue.map((t) => t.title)).toEqual(["Overdue"]);
+      expect(body.today.map((t) => t.title)).toEqual(["Today"]);
+      expect(body.snoozed).toBeUndefined();
+    });
+
+    it("正常系: include_completed=trueで完了済みのTodoも含める", async () => {
+      await createTestTodo({ userId, title: "Done", dueDate: today, status: 2 });
+
+      const response = await app.request("/api/v1/todos/agenda?tz=UTC&include_completed=true", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      const body = await parseResponse(response, agendaResponseSchema);
+      expect(body.today.map((t) => t.title)).toEqual(["Done"]);
+    });
+
+    it("正常系: include_snoozed=trueで今日スヌーズが明けるTodoを含める", async () => {
+      await createTestTodo({
+        userId,
+        title: "Wakes today",
+        dueDate: daysFromToday(5),
+        snoozedUntil: today,
+      });
+      await createTestTodo({
+        userId,
+        title: "Still snoozed",
+        dueDate: daysFromToday(-1),
+        snoozedUntil: daysFromToday(2),
+      });
+
+      const withoutSnoozed = await app.request("/api/v1/todos/agenda?tz=UTC", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const plain = await parseResponse(withoutSnoozed, agendaResponseSchema);
+      expect(plain.overdue).toHaveLength(0);
+      expect(plain.today).toHaveLength(0);
+
+      const response = await app.request("/api/v1/todos/agenda?tz=UTC&include_snoozed=true", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const body = await parseResponse(response, agendaResponseSchema);
+      expect(body.overdue).toHaveLength(0);
+      expect(body.snoozed?.map((t) => t.title)).toEqual(["Wakes today"]);
+    });
+
+    it("正常系: 他ユーザーのTodoは含まれない", async () => {
+      const otherUser = await createTestUser("other@example.com");
+      await createTestTodo({ userId: otherUser.userId, title: "Other's", dueDate: today });
+
+      const response = await app.request("/api/v1/todos/agenda?tz=UTC", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      const body = await parseResponse(response, agendaResponseSchema);
+      expect(body.today).toHaveLength(0);
+    });
+
+    it("異常系: 不正なタイムゾーンは400エラー", async () => {
+      const response = await app.request("/api/v1/todos/agenda?tz=Invalid/Zone", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("VALIDATION_ERROR");
+    });
+  });
+
+  describe("GET /api/v1/todos/trends - 作成数・完了数の推移", () => {
+    const fetchTrends = (query: string) =>
+      app.request(`/api/v1/todos/trends?${query}`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+    it("正常系: 日ごとの作成数と完了数を件数0の日も含めて返す", async () => {
+      await createTestTodo({ userId, title: "A", createdAt: new Date("2024-01-01T10:00:00Z") });
+      await createTestTodo({ userId, title: "B", createdAt: new Date("2024-01-01T23:00:00Z") });
+      await createTestTodo({
+        userId,
+        title: "C",
+        status: 2,
+        createdAt: new Date("2024-01-01T12:00:00Z"),
+        completedAt: new Date("2024-01-03T09:00:00Z"),
+      });
+      const other = await createTestUser("trends-other@example.com");
+      await createTestTodo({
+        userId: other.userId,
+        title: "Other",
+        createdAt: new Date("2024-01-02T10:00:00Z"),
+      });
+
+      const response = await fetchTrends("from=2024-01-01&to=2024-01-03&tz=UTC");
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoTrendsResponseSchema);
+      expect(body).toEqual([
+        { date: "2024-01-01", created: 3, completed: 0 },
+        { date: "2024-01-02", created: 0, completed: 0 },
+        { date: "2024-01-03", created: 0, completed: 1 },
+      ]);
+    });
+
+    it("正常系: tz で指定したタイムゾーンの日付で集計する", async () => {
+      await createTestTodo({ userId, title: "A", createdAt: new Date("2024-01-01T20:00:00Z") });
+
+      const response = await fetchTrends("from=2024-01-01&to=2024-01-02&tz=Asia/Tokyo");
+
+      const body = await parseResponse(response, todoTrendsResponseSchema);
+      expect(body).toEqual([
+        { date: "2024-01-01", created: 0, completed: 0 },
+        { date: "2024-01-02", created: 1, completed: 0 },
+      ]);
+    });
+
+    it("正常系: bucket=week の場合は週（月曜日始まり）ごとに集計する", async () => {
+      await createTestTodo({ userId, title: "A", createdAt: new Date("2024-01-03T10:00:00Z") });
+      await createTestTodo({ userId, title: "B", createdAt: new Date("2024-01-07T10:00:00Z") });
+      await createTestTodo({ userId, title: "C", createdAt: new Date("2024-01-08T10:00:00Z") });
+
+      const response = await fetchTrends("from=2024-01-03&to=2024-01-10&bucket=week&tz=UTC");
+
+      const body = await parseResponse(response, todoTrendsResponseSchema);
+      expect(body).toEqual([
+        { date: "2024-01-01", created: 2, completed: 0 },
+        { date: "2024-01-08", created: 1, completed: 0 },
+      ]);
+    });
+
+    it("正常系: bucket=week の場合は from より前でも先頭の週の月曜日から集計する", async () => {
+      await createTestTodo({ userId, title: "A", createdAt: new Date("2024-01-01T10:00:00Z") });
+      await createTestTodo({ userId, title: "B", createdAt: new Date("2024-01-05T10:00:00Z") });
+
+      const response = await fetchTrends("from=2024-01-04&to=2024-01-07&bucket=week&tz=UTC");
+
+      const body = await parseResponse(response, todoTrendsResponseSchema);
+      expect(body).toEqual([{ date: "2024-01-01", created: 2, completed: 0 }]);
+    });
+
+    it("異常系: from が to より後の場合は400", async () => {
+      const response = await fetchTrends("from=2024-02-01&to=2024-01-01");
+
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.details).toHaveProperty("from");
+    });
+
+    it("異常系: 期間が長すぎる場合は400", async () => {
+      const response = await fetchTrends("from=2023-01-01&to=2024-12-31");
+
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.details).toHaveProperty("to");
+    });
+
+    it("異常系: from / to がない場合は400", async () => {
+      const response = await fetchTrends("bucket=day");
+
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.details).toHaveProperty("from");
+      expect(body.error.details).toHaveProperty("to");
+    });
+  });
+
+  describe("GET /api/v1/todos/batch - 一括取得", () => {
+    const fetchBatch = (ids: string) =>
+      app.request(`/api/v1/todos/batch?ids=${ids}`, {
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+    it("正常系: 指定した順にリレーション付きで返す", async () => {
+      const tagId = await createTestTag(userId, "Tag");
+      const todo1 = await createTestTodo({ userId, title: "Todo 1" });
+      const todo2 = await createTestTodo({ userId, title: "Todo 2" });
+      const todo3 = await createTestTodo({ userId, title: "Todo 3" });
+      await attachTagToTodo(todo2, tagId);
+
+      const response = await fetchBatch(`${todo3},${todo1},${todo2}`);
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoListResponseSchema);
+      expect(body.map((t) => t.id)).toEqual([todo3, todo1, todo2]);
+      expect(body[2].tags.map((t) => t.id)).toEqual([tagId]);
+    });
+
+    it("正常系: 存在しないIDや他ユーザーのTodoのIDは除外する", async () => {
+      const otherUser = await createTestUser("batch-other@example.com");
+      const mine = await createTestTodo({ userId, title: "Mine" });
+      const theirs = await createTestTodo({ userId: otherUser.userId, title: "Theirs" });
+
+      const response = await fetchBatch(`${theirs},999999,${mine}`);
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoListResponseSchema);
+      expect(body.map((t) => t.id)).toEqual([mine]);
+    });
+
+    it("正常系: 重複したIDは1件のみ返す", async () => {
+      const todoId = await createTestTodo({ userId, title: "Todo" });
+
+      const response = await fetchBatch(`${todoId},${todoId}`);
+
+      const body = await parseResponse(response, todoListResponseSchema);
+      expect(body.map((t) => t.id)).toEqual([todoId]);
+    });
+
+    it("異常系: IDが上限を超える場合は400エラー", async () => {
+      const ids = Array.from({ length: 101 }, (_, i) => i + 1).join(",");
+
+      const response = await fetchBatch(ids);
+
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.details?.ids).toBeDefined();
+    });
+
+    it("異常系: 不正なIDは400エラー", async () => {
+      const response = await fetchBatch("1,abc");
+
+      expect(response.status).toBe(400);
+    });
+  });
+
+  describe("POST/DELETE /api/v1/todos/:id/star - スター", () => {
+    it("正常系: スターを付けて外せる", async () => {
       const createResponse = await app.request("/api/v1/todos", {
         method: "POST",
         headers: {
           "Content-Type": "application/json",
           Authorization: `Bearer ${token}`,
         },
-        body: JSON.stringify({ title: "To be deleted" }),
+        body: JSON.stringify({ title: "Star me", priority: "low" }),
       });
       const created = await parseResponse(createResponse, todoResponseSchema);
+      expect(created.starred).toBe(false);
 
-      const response = await app.request(`/api/v1/todos/${created.id}`, {
+      const starResponse = await app.request(`/api/v1/todos/${created.id}/star`, {
+        method: "POST",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      expect(starResponse.status).toBe(200);
+      const starred = await parseResponse(starResponse, todoResponseSchema);
+      expect(starred.starred).toBe(true);
+      expect(starred.priority).toBe("low"); // 優先度は変更されない
+
+      const unstarResponse = await app.request(`/api/v1/todos/${created.id}/star`, {
         method: "DELETE",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      expect(unstarResponse.status).toBe(200);
+      const unstarred = await parseResponse(unstarResponse, todoResponseSchema);
+      expect(unstarred.starred).toBe(false);
+    });
+
+    it("異常系: 存在しないTodoで404エラー", async () => {
+      const response = await app.request("/api/v1/todos/99999/star", {
+        method: "POST",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+
+      expect(response.status).toBe(404);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("NOT_FOUND");
+    });
+  });
+
+  describe("POST /api/v1/todos/bulk_tags - タグ一括付け外し", () => {
+    /**
+     * APIでTodoを作成する
+     * @param body - 作成リクエストボディ
+     * @returns 作成されたTodo
+     */
+    async function createTodo(body: Record<string, unknown>) {
+      const response = await app.request("/api/v1/todos", {
+        method: "POST",
         headers: {
+          "Content-Type": "application/json",
           Authorization: `Bearer ${token}`,
         },
+        body: JSON.stringify(body),
       });
+      return parseResponse(response, todoResponseSchema);
+    }
 
-      expect(response.status).toBe(204);
+    it("正常系: 複数のTodoにタグを追加・削除できる（既存の紐付けはスキップ）", async () => {
+      const tagA = await createTestTag(userId, "tag-a");
+      const tagB = await createTestTag(userId, "tag-b");
+      const todo1 = await createTodo({ title: "Todo 1", tag_ids: [tagA, tagB] });
+      const todo2 = await createTodo({ title: "Todo 2" });
 
-      // 削除確認
-      const getResponse = await app.request(`/api/v1/todos/${created.id}`, {
-        method: "GET",
+      const response = await app.request("/api/v1/todos/bulk_tags", {
+        method: "POST",
         headers: {
+          "Content-Type": "application/json",
           Authorization: `Bearer ${token}`,
         },
+        body: JSON.stringify({
+          todo_ids: [todo1.id, todo2.id],
+          add_tag_ids: [tagA],
+          remove_tag_ids: [tagB],
+        }),
       });
-      expect(getResponse.status).toBe(404);
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, bulkTagsResponseSchema);
+      expect(body.todo_ids).toEqual([todo1.id, todo2.id]);
+      expect(body.added_count).toBe(1); // todo1 の tagA は既存のためスキップ
+      expect(body.removed_count).toBe(1);
+
+      const listResponse = await app.request("/api/v1/todos", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const list = await parseResponse(listResponse, todoListResponseSchema);
+      for (const todo of list) {
+        expect(todo.tags.map((t) => t.id)).toEqual([tagA]);
+      }
     });
 
-    it("異常系: 存在しないIDで404エラー", async () => {
-      const response = await app.request("/api/v1/todos/99999", {
-        method: "DELETE",
+    it("異常系: 他ユーザーのTodoが含まれる場合403エラー", async () => {
+      const otherUser = await createTestUser("todo-other@example.com");
+      const tagId = await createTestTag(userId, "mine");
+      const mine = await createTodo({ title: "Mine" });
+      const othersTodo = await createTestTodo({ userId: otherUser.userId, title: "Other's" });
+
+      const response = await app.request("/api/v1/todos/bulk_tags", {
+        method: "POST",
         headers: {
+          "Content-Type": "application/json",
           Authorization: `Bearer ${token}`,
         },
+        body: JSON.stringify({ todo_ids: [mine.id, othersTodo], add_tag_ids: [tagId] }),
       });
 
-      expect(response.status).toBe(404);
+      expect(response.status).toBe(403);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("FORBIDDEN");
     });
 
-    it("異常系: 他ユーザーのTodoで404エラー", async () => {
+    it("異常系: 他ユーザーのタグが含まれる場合400エラー", async () => {
       const otherUser = await createTestUser("todo-other@example.com");
-      const createResponse = await app.request("/api/v1/todos", {
+      const othersTag = await createTestTag(otherUser.userId, "others");
+      const mine = await createTodo({ title: "Mine" });
+
+      const response = await app.request("/api/v1/todos/bulk_tags", {
         method: "POST",
         headers: {
           "Content-Type": "application/json",
-          Authorization: `Bearer ${otherUser.token}`,
+          Authorization: `Bearer ${token}`,
         },
-        body: JSON.stringify({ title: "Other's todo" }),
+        body: JSON.stringify({ todo_ids: [mine.id], add_tag_ids: [othersTag] }),
       });
-      const created = await parseResponse(createResponse, todoResponseSchema);
 
-      const response = await app.request(`/api/v1/todos/${created.id}`, {
-        method: "DELETE",
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.details?.add_tag_ids).toEqual([
+        `ID ${othersTag} は存在しないか、使用できません`,
+      ]);
+    });
+
+    it("異常系: 追加・削除のどちらも指定しない場合400エラー", async () => {
+      const mine = await createTodo({ title: "Mine" });
+
+      const response = await app.request("/api/v1/todos/bulk_tags", {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${token}`,
+        },
+        body: JSON.stringify({ todo_ids: [mine.id] }),
+      });
+
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("VALIDATION_ERROR");
+    });
+  });
+
+  describe("POST /api/v1/todos/:id/move - 移動", () => {
+    /**
+     * Todoを移動する
+     * @param id - 移動するTodoのID
+     * @param data - 移動先
+     * @returns レスポンス
+     */
+    const moveTodo = (id: number, data: Record<string, unknown>) =>
+      app.request(`/api/v1/todos/${id}/move`, {
+        method: "POST",
         headers: {
+          "Content-Type": "application/json",
           Authorization: `Bearer ${token}`,
         },
+        body: JSON.stringify(data),
+      });
+
+    /**
+     * 一覧のタイトルをposition順に取得する
+     * @returns タイトルの配列
+     */
+    const listTitles = async () => {
+      const response = await app.request("/api/v1/todos", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const list = await parseResponse(response, todoListResponseSchema);
+      return list.map((t) => t.title);
+    };
+
+    it("正常系: after_idで指定したTodoの直後に移動する", async () => {
+      const a = await createTestTodo({ userId, title: "A", position: 0 });
+      await createTestTodo({ userId, title: "B", position: 1 });
+      await createTestTodo({ userId, title: "C", position: 2 });
+      const d = await createTestTodo({ userId, title: "D", position: 3 });
+
+      const response = await moveTodo(d, { after_id: a });
+
+      expect(response.status).toBe(200);
+      expect(await listTitles()).toEqual(["A", "D", "B", "C"]);
+    });
+
+    it("正常系: 移動先に空きがない場合は全体を振り直してから間に挿入する", async () => {
+      const updatedAt = new Date("2024-01-01T00:00:00Z");
+      const a = await createTestTodo({ userId, title: "A", position: 0, updatedAt });
+      const b = await createTestTodo({ userId, title: "B", position: 1, updatedAt });
+      const c = await createTestTodo({ userId, title: "C", position: 2, updatedAt });
+
+      const response = await moveTodo(c, { after_id: a });
+
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.position).toBe(1500);
+      const list = await app.request("/api/v1/todos", {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
+      });
+      const todos = await parseResponse(list, todoListResponseSchema);
+      expect(todos.map((t) => [t.id, t.position])).toEqual([
+        [a, 1000],
+        [c, 1500],
+        [b, 2000],
+      ]);
+      // 振り直されただけのTodoは updated_at が変わらない
+      expect(todos.filter((t) => t.id !== c).map((t) => t.updated_at)).toEqual([
+        updatedAt.toISOString(),
+        updatedAt.toISOString(),
+      ]);
+      expect(body.updated_at).not.toBe(updatedAt.toISOString());
+    });
+
+    it("正常系: before_idで指定したTodoの直前に移動する", async () => {
+      const a = await createTestTodo({ userId, title: "A", position: 0 });
+      await createTestTodo({ userId, title: "B", position: 1 });
+      const c = await createTestTodo({ userId, title: "C", position: 2 });
+
+      const response = await moveTodo(c, { before_id: a });
+
+      expect(response.status).toBe(200);
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.position).toBeGreaterThanOrEqual(0);
+      expect(await listTitles()).toEqual(["C", "A", "B"]);
+    });
+
+    it("正常系: 移動先が空いている場合は他のTodoのpositionを変更しない", async () => {
+      const a = await createTestTodo({ userId, title: "A", position: 0 });
+      const b = await createTestTodo({ userId, title: "B", position: 5 });
+      const c = await createTestTodo({ userId, title: "C", position: 10 });
+
+      await moveTodo(c, { after_id: a });
+
+      const response = await app.request(`/api/v1/todos/${b}`, {
+        method: "GET",
+        headers: { Authorization: `Bearer ${token}` },
       });
+      const body = await parseResponse(response, todoResponseSchema);
+      expect(body.position).toBe(5);
+      expect(await listTitles()).toEqual(["A", "C", "B"]);
+    });
+
+    it("異常系: after_idとbefore_idの両方を指定すると400エラー", async () => {
+      const a = await createTestTodo({ userId, title: "A", position: 0 });
+      const b = await createTestTodo({ userId, title: "B", position: 1 });
+      const c = await createTestTodo({ userId, title: "C", position: 2 });
+
+      const response = await moveTodo(c, { after_id: a, before_id: b });
+
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("VALIDATION_ERROR");
+    });
+
+    it("異常系: 自身を基準に指定すると400エラー", async () => {
+      const a = await createTestTodo({ userId, title: "A", position: 0 });
+
+      const response = await moveTodo(a, { after_id: a });
+
+      expect(response.status).toBe(400);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.details?.after_id).toEqual(["自身を基準に移動することはできません"]);
+    });
+
+    it("異常系: 他ユーザーのTodoを基準に指定すると404エラー", async () => {
+      const a = await createTestTodo({ userId, title: "A", position: 0 });
+      const otherUser = await createTestUser("other@example.com");
+      const othersTodo = await createTestTodo({ userId: otherUser.userId, title: "Other's" });
+
+      const response = await moveTodo(a, { after_id: othersTodo });
 
       expect(response.status).toBe(404);
+      const body = await parseResponse(response, errorResponseSchema);
+      expect(body.error.code).toBe("NOT_FOUND");
     });
   });
 
@@ -665,4 +2414,37 @@ describe("Todo API", () => {
       expect(body.error.code).toBe("VALIDATION_ERROR");
     });
   });
+
+  describe("優先度の既定色 - PRIORITY_COLOR_*", () => {
+    /** PRIORITY_COLOR_* を上書きした設定でTodoServiceを作成する */
+    const createService = () => {
+      const db = getDb();
+      return new TodoService(
+        db,
+        getTodoRepository(),
+        new TodoCategoryRepository(db),
+        new TodoTagValidatorRepository(db),
+        getRepositoryFactories(),
+        { low: "#000000", medium: "#111111", high: "#222222" },
+      );
+    };
+
+    it("正常系: カテゴリ未設定のTodoは上書きした優先度の色を effective_color に使う", async () => {
+      const lowId = await createTestTodo({ userId, title: "Low", priority: 0 });
+      const highId = await createTestTodo({ userId, title: "High", priority: 2 });
+      const service = createService();
+
+      expect((await service.show(lowId, userId)).effective_color).toBe("#000000");
+      expect((await service.show(highId, userId)).effective_color).toBe("#222222");
+    });
+
+    it("正常系: カテゴリ設定済みのTodoは上書きしてもカテゴリの色を使う", async () => {
+      const categoryId = await createTestCategory(userId, "Work", "#3B82F6");
+      const todoId = await createTestTodo({ userId, title: "Task", priority: 2, categoryId });
+
+      const todo = await createService().show(todoId, userId);
+
+      expect(todo.effective_color).toBe("#3B82F6");
+    });
+  });
 });